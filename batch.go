@@ -0,0 +1,111 @@
+package split_csv
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+var (
+	ErrWrongBatchSize      = errors.New("batch size should be positive")
+	ErrRejectsNotSupported = errors.New("rejected records can't be written without chunks")
+)
+
+// errBatchStopped stops splitting when a batch has failed
+var errBatchStopped = errors.New("batch processing is stopped")
+
+// recordSink receives fields of every record with a number of its last source line
+type recordSink func(st *state, fields []string, lastLine int) error
+
+// Batch contains parsed records passed to the batch function
+// Index - an index of the batch starting from 1
+// Header - names of the fields, it's nil when the input has no header
+// Records - fields of the records
+// Lines - numbers of the first source lines of the records
+// FirstLine, LastLine - a range of source lines of the records starting from 1
+type Batch struct {
+	Index     int
+	Header    []string
+	Records   [][]string
+	Lines     []int
+	FirstLine int
+	LastLine  int
+}
+
+// ForEachBatch parses records of the source and passes them in batches to the function which is run
+// by the given number of workers. Records go through filters, transformations and other options of
+// the splitter, but no chunks are created. Processing stops on the first error which is returned.
+func (s Splitter) ForEachBatch(source io.Reader, batchSize int, workers int, fn func(batch Batch) error) error {
+	if batchSize <= 0 {
+		return ErrWrongBatchSize
+	}
+	if s.Parts > 0 {
+		return ErrPartsNotSupported
+	}
+	if s.Malformed == MalformedReject {
+		return ErrRejectsNotSupported
+	}
+	workers = max(workers, 1)
+	batches := make(chan Batch)
+	stop := make(chan struct{})
+	var batchErr error
+	var once sync.Once
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				if err := fn(batch); err != nil {
+					once.Do(func() {
+						batchErr = &BatchError{
+							Batch:     batch.Index,
+							FirstLine: batch.FirstLine,
+							LastLine:  batch.LastLine,
+							Err:       err,
+						}
+						close(stop)
+					})
+				}
+			}
+		}()
+	}
+
+	batch := Batch{Index: 1}
+	send := func() error {
+		select {
+		case batches <- batch:
+		case <-stop:
+			return errBatchStopped
+		}
+		batch = Batch{Index: batch.Index + 1, Header: batch.Header}
+		return nil
+	}
+	s.recordSink = func(st *state, fields []string, lastLine int) error {
+		if len(batch.Records) == 0 {
+			batch.Header = st.outputHeader()
+			batch.FirstLine = st.recordLine
+		}
+		batch.Records = append(batch.Records, fields)
+		batch.Lines = append(batch.Lines, st.recordLine)
+		batch.LastLine = lastLine
+		if len(batch.Records) < batchSize {
+			return nil
+		}
+		return send()
+	}
+	err := s.validate()
+	if err == nil {
+		_, err = s.splitReader(source, "", "", nil)
+	}
+	if err == nil && len(batch.Records) > 0 {
+		err = send()
+	}
+	close(batches)
+	wg.Wait()
+	if batchErr != nil {
+		return batchErr
+	}
+
+	return err
+}
@@ -0,0 +1,105 @@
+package split_csv
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const batchInput = `id,comment
+1,"multi
+line"
+2,second
+3,third
+4,"another
+multi
+line"
+5,fifth
+`
+
+func Test_ForEachBatch_integration(t *testing.T) {
+	t.Run("It passes parsed records in batches", func(t *testing.T) {
+		var mu sync.Mutex
+		var batches []Batch
+		err := New().ForEachBatch(strings.NewReader(batchInput), 2, 3, func(batch Batch) error {
+			mu.Lock()
+			defer mu.Unlock()
+			batches = append(batches, batch)
+			return nil
+		})
+
+		require.NoError(t, err)
+		sort.Slice(batches, func(i, j int) bool {
+			return batches[i].Index < batches[j].Index
+		})
+		header := []string{"id", "comment"}
+		assert.Equal(t, []Batch{
+			{
+				Index:     1,
+				Header:    header,
+				Records:   [][]string{{"1", "multi\nline"}, {"2", "second"}},
+				Lines:     []int{2, 4},
+				FirstLine: 2,
+				LastLine:  4,
+			},
+			{
+				Index:     2,
+				Header:    header,
+				Records:   [][]string{{"3", "third"}, {"4", "another\nmulti\nline"}},
+				Lines:     []int{5, 6},
+				FirstLine: 5,
+				LastLine:  8,
+			},
+			{Index: 3, Header: header, Records: [][]string{{"5", "fifth"}}, Lines: []int{9}, FirstLine: 9, LastLine: 9},
+		}, batches)
+	})
+	t.Run("It applies options of the splitter", func(t *testing.T) {
+		s := New()
+		s.WithHeader = false
+		s.ColumnIndexes = []int{1}
+		s.Filters = []RowFilter{{Index: 1, Op: FilterMatches, Value: "multi"}}
+		var records [][]string
+		err := s.ForEachBatch(strings.NewReader(batchInput), 10, 1, func(batch Batch) error {
+			assert.Nil(t, batch.Header)
+			records = append(records, batch.Records...)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"multi\nline"}, {"another\nmulti\nline"}}, records)
+	})
+	t.Run("It stops on the first error", func(t *testing.T) {
+		batchErr := errors.New("batch error")
+		var mu sync.Mutex
+		processed := 0
+		input := "id\n" + strings.Repeat("1\n", 1000)
+		err := New().ForEachBatch(strings.NewReader(input), 1, 2, func(batch Batch) error {
+			mu.Lock()
+			defer mu.Unlock()
+			processed++
+			if batch.Index == 3 {
+				return batchErr
+			}
+			return nil
+		})
+
+		var target *BatchError
+		require.True(t, errors.As(err, &target))
+		assert.Equal(t, 3, target.Batch)
+		assert.Equal(t, 4, target.FirstLine)
+		assert.ErrorIs(t, err, batchErr)
+		assert.Less(t, processed, 1000)
+	})
+	t.Run("It fails on wrong batch size", func(t *testing.T) {
+		err := New().ForEachBatch(strings.NewReader(batchInput), 0, 1, func(batch Batch) error {
+			return nil
+		})
+
+		assert.ErrorIs(t, err, ErrWrongBatchSize)
+	})
+}
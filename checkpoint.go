@@ -0,0 +1,198 @@
+package split_csv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+var (
+	ErrNoCheckpointPath  = errors.New("checkpoint path is not set")
+	ErrPartsNotResumable = errors.New("parts option can't be combined with checkpoints")
+)
+
+// Checkpoint contains the progress of splitting which is saved after each chunk
+// Offset - a position in the source right after BrokenLine where reading should be continued
+// Chunk - an index of the next chunk
+// Completed - whether the split has been finished
+type Checkpoint struct {
+	Offset       int64    `json:"offset"`
+	Chunk        int      `json:"chunk"`
+	Header       []byte   `json:"header"`
+	BrokenLine   []byte   `json:"broken_line"`
+	ColumnsCount int      `json:"columns_count"`
+	Result       []string `json:"result"`
+	Line         int      `json:"line"`
+	FieldsCount  int      `json:"fields_count"`
+	Columns      []int    `json:"columns"`
+	HeaderFields []string `json:"header_fields"`
+	DroppedRows  int      `json:"dropped_rows"`
+	RejectedRows int      `json:"rejected_rows"`
+	Completed    bool     `json:"completed"`
+}
+
+// Resume continues splitting of the file from the checkpoint stored in CheckpointPath.
+// The file is split from the beginning when there is no checkpoint yet.
+func (s Splitter) Resume(inputFilePath string, outputDirPath string) ([]string, error) {
+	if s.Parts > 0 {
+		return nil, ErrPartsNotSupported
+	}
+	cp, err := s.loadCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+	if cp == nil {
+		return s.Split(inputFilePath, outputDirPath)
+	}
+	if cp.Completed {
+		return cp.Result, nil
+	}
+
+	file, err := s.fileOp.Open(inputFilePath)
+	if err != nil {
+		return nil, &InputError{Op: "open", Path: inputFilePath, Err: err}
+	}
+	defer file.Close()
+	source, ok := file.(io.ReadSeeker)
+	if !ok {
+		return nil, ErrNotSeekable
+	}
+
+	return s.resumeReader(source, outputDirPath, getFileName(inputFilePath), cp)
+}
+
+// ResumeReader continues splitting of the reader from the checkpoint stored in CheckpointPath
+func (s Splitter) ResumeReader(
+	source io.ReadSeeker,
+	outputDirPath string,
+	outputFilePrefix string,
+) ([]string, error) {
+	if s.Parts > 0 {
+		return nil, ErrPartsNotSupported
+	}
+	cp, err := s.loadCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+	if cp == nil {
+		return s.SplitReader(source, outputDirPath, outputFilePrefix)
+	}
+	if cp.Completed {
+		return cp.Result, nil
+	}
+
+	return s.resumeReader(source, outputDirPath, outputFilePrefix, cp)
+}
+
+func (s Splitter) resumeReader(
+	source io.ReadSeeker,
+	outputDirPath string,
+	outputFilePrefix string,
+	cp *Checkpoint,
+) ([]string, error) {
+	if _, err := source.Seek(cp.Offset, io.SeekStart); err != nil {
+		return nil, &InputError{Op: "seek", Offset: cp.Offset, Err: err}
+	}
+
+	result, err := s.splitReader(source, outputDirPath, outputFilePrefix, cp)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Chunks, nil
+}
+
+// validateCheckpoint checks that the split can be continued from its checkpoints
+func (s Splitter) validateCheckpoint() error {
+	if s.Parts > 0 && s.CheckpointPath != "" {
+		return ErrPartsNotResumable
+	}
+
+	return nil
+}
+
+// loadCheckpoint reads the checkpoint file, nil is returned when it doesn't exist
+func (s Splitter) loadCheckpoint() (*Checkpoint, error) {
+	if s.CheckpointPath == "" {
+		return nil, ErrNoCheckpointPath
+	}
+	file, err := s.fileOp.Open(s.CheckpointPath)
+	if s.fileOp.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, &CheckpointError{Op: "open", Path: s.CheckpointPath, Err: err}
+	}
+	defer file.Close()
+	cp := &Checkpoint{}
+	if err := json.NewDecoder(file).Decode(cp); err != nil {
+		return nil, &CheckpointError{Op: "decode", Path: s.CheckpointPath, Err: err}
+	}
+
+	return cp, nil
+}
+
+// saveCheckpoint records the progress into the checkpoint file if it's enabled
+func (s Splitter) saveCheckpoint(st *state, completed bool) error {
+	if s.CheckpointPath == "" || s.DryRun {
+		return nil
+	}
+	offset := st.offset
+	if st.fileBuffer != nil {
+		offset -= int64(st.fileBuffer.Len())
+	}
+	brokenLine := st.brokenLine
+	line := 0
+	if s.isRecordMode() {
+		// records which aren't written yet are read again after resuming
+		offset = st.lineOffset - int64(len(st.pending)+len(st.record))
+		brokenLine = nil
+		line = st.line - bytes.Count(st.pending, []byte{'\n'}) - bytes.Count(st.record, []byte{'\n'})
+	}
+	cp := Checkpoint{
+		Offset:       offset,
+		Chunk:        st.chunk,
+		Header:       st.header,
+		BrokenLine:   brokenLine,
+		ColumnsCount: st.columnsCount,
+		Result:       st.result,
+		Line:         line,
+		FieldsCount:  st.fieldsCount,
+		Columns:      st.columns,
+		HeaderFields: st.headerFields,
+		DroppedRows:  st.dropped,
+		RejectedRows: st.rejected,
+		Completed:    completed,
+	}
+	// the checkpoint is replaced atomically, so a crash never leaves it partially written
+	tmpPath := s.CheckpointPath + ".tmp"
+	file, err := s.fileOp.Create(tmpPath)
+	if err != nil {
+		return &CheckpointError{Op: "create", Path: tmpPath, Err: err}
+	}
+	if err := writeCheckpoint(file, cp); err != nil {
+		file.Close()
+		return &CheckpointError{Op: "write", Path: tmpPath, Err: err}
+	}
+	if err := file.Close(); err != nil {
+		return &CheckpointError{Op: "write", Path: tmpPath, Err: err}
+	}
+	if err := s.fileOp.Rename(tmpPath, s.CheckpointPath); err != nil {
+		return &CheckpointError{Op: "replace", Path: s.CheckpointPath, Err: err}
+	}
+
+	return nil
+}
+
+// writeCheckpoint encodes the checkpoint to the file and flushes it to the disk
+func writeCheckpoint(file io.Writer, cp Checkpoint) error {
+	if err := json.NewEncoder(file).Encode(cp); err != nil {
+		return err
+	}
+	if syncer, ok := file.(interface{ Sync() error }); ok {
+		return syncer.Sync()
+	}
+
+	return nil
+}
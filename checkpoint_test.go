@@ -0,0 +1,176 @@
+package split_csv
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingReader struct {
+	r     io.Reader
+	limit int
+	read  int
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.read >= r.limit {
+		return 0, errors.New("connection lost")
+	}
+	if len(p) > r.limit-r.read {
+		p = p[:r.limit-r.read]
+	}
+	n, err := r.r.Read(p)
+	r.read += n
+
+	return n, err
+}
+
+// crashingOp fails in the middle of writing of created files
+type crashingOp struct {
+	fileOp
+}
+
+func (o crashingOp) Create(name string) (io.WriteCloser, error) {
+	file, err := o.fileOp.Create(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &crashingFile{WriteCloser: file}, nil
+}
+
+type crashingFile struct {
+	io.WriteCloser
+}
+
+func (f *crashingFile) Write(p []byte) (int, error) {
+	n, _ := f.WriteCloser.Write(p[:len(p)/2])
+
+	return n, errors.New("disk is full")
+}
+
+func Test_Resume_integration(t *testing.T) {
+	input := "testdata/test_multiline_cells.csv"
+	for name, strict := range map[string]bool{"split": false, "strict split": true} {
+		t.Run("It resumes an interrupted "+name, func(t *testing.T) {
+			dir := t.TempDir()
+			s := newTestSplitter(";", 300)
+			s.bufferSize = 100
+			s.CheckpointPath = filepath.Join(dir, "checkpoint.json")
+			s.StrictChunkSize = strict
+			file, err := os.Open(input)
+			require.NoError(t, err)
+			defer file.Close()
+			_, err = s.SplitReader(&failingReader{r: file, limit: 1250}, dir, "test_multiline_cells")
+			assert.EqualError(t, err, "Couldn't read file bulk: connection lost")
+
+			cp, err := s.loadCheckpoint()
+			require.NoError(t, err)
+			require.NotNil(t, cp)
+			assert.False(t, cp.Completed)
+			assert.Greater(t, cp.Chunk, 1)
+
+			result, err := s.Resume(input, dir)
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join(dir, "test_multiline_cells_1.csv"), result[0])
+			source, _ := os.ReadFile(input)
+			assert.Equal(t, string(source), string(joinChunks(t, result, cp.Header)))
+
+			cp, err = s.loadCheckpoint()
+			require.NoError(t, err)
+			assert.True(t, cp.Completed)
+			completed, err := s.Resume(input, dir)
+			assert.NoError(t, err)
+			assert.Equal(t, result, completed)
+		})
+	}
+	t.Run("It splits from the beginning without a checkpoint", func(t *testing.T) {
+		dir := t.TempDir()
+		s := newTestSplitter(";", 300)
+		s.bufferSize = 100
+		s.CheckpointPath = filepath.Join(dir, "checkpoint.json")
+		result, err := s.Resume(input, dir)
+		assert.NoError(t, err)
+		assert.Len(t, result, 11)
+	})
+	t.Run("It keeps counters of skipped records", func(t *testing.T) {
+		dir := t.TempDir()
+		s := newTestSplitter(";", 300)
+		s.bufferSize = 100
+		s.CheckpointPath = filepath.Join(dir, "checkpoint.json")
+		s.Malformed = MalformedSkip
+		var input strings.Builder
+		input.WriteString("id;name\n")
+		for i := 1; i <= 40; i++ {
+			if i == 5 || i == 35 {
+				input.WriteString("bad\n")
+				continue
+			}
+			fmt.Fprintf(&input, "%d;value %03d\n", i, i)
+		}
+		_, err := s.SplitReader(&failingReader{r: strings.NewReader(input.String()), limit: 400}, dir, "test")
+		require.Error(t, err)
+
+		_, err = s.ResumeReader(strings.NewReader(input.String()), dir, "test")
+		require.NoError(t, err)
+
+		cp, err := s.loadCheckpoint()
+		require.NoError(t, err)
+		assert.True(t, cp.Completed)
+		assert.Equal(t, 2, cp.RejectedRows)
+	})
+	t.Run("It keeps the previous checkpoint when saving fails", func(t *testing.T) {
+		dir := t.TempDir()
+		s := newTestSplitter(";", 300)
+		s.bufferSize = 100
+		s.CheckpointPath = filepath.Join(dir, "checkpoint.json")
+		_, err := s.Split(input, dir)
+		require.NoError(t, err)
+
+		s.fileOp = crashingOp{}
+		st, err := s.start(dir, "test_multiline_cells", nil)
+		require.NoError(t, err)
+		assert.ErrorContains(t, s.saveCheckpoint(st, false), "disk is full")
+
+		cp, err := s.loadCheckpoint()
+		require.NoError(t, err)
+		assert.True(t, cp.Completed)
+	})
+	t.Run("It fails without a checkpoint path", func(t *testing.T) {
+		result, err := newTestSplitter(";", 300).Resume(input, t.TempDir())
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrNoCheckpointPath)
+	})
+	t.Run("It doesn't split on parts with checkpoints", func(t *testing.T) {
+		s := newTestSplitter(";", 300)
+		s.Parts = 3
+		s.CheckpointPath = filepath.Join(t.TempDir(), "checkpoint.json")
+		result, err := s.Split(input, t.TempDir())
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrPartsNotResumable)
+	})
+}
+
+// joinChunks concatenates chunks skipping the header in all of them except the first one
+func joinChunks(t *testing.T, chunks []string, header []byte) []byte {
+	var result []byte
+	for i, chunk := range chunks {
+		content, err := os.ReadFile(chunk)
+		require.NoError(t, err)
+		if i > 0 {
+			require.True(t, bytes.HasPrefix(content, header))
+			content = content[len(header):]
+		}
+		result = append(result, content...)
+	}
+
+	return result
+}
@@ -0,0 +1,125 @@
+package split_csv
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"path/filepath"
+)
+
+// Checksum defines an algorithm of chunk checksums
+type Checksum int
+
+const (
+	// ChecksumSHA256 is a SHA-256 digest written to <chunk>.sha256
+	ChecksumSHA256 Checksum = iota + 1
+	// ChecksumMD5 is an MD5 digest written to <chunk>.md5
+	ChecksumMD5
+)
+
+// checksumHashes are constructors of hashes by checksum algorithms
+var checksumHashes = map[Checksum]func() hash.Hash{
+	ChecksumSHA256: sha256.New,
+	ChecksumMD5:    md5.New,
+}
+
+// checksumExtensions are extensions of checksum files by algorithms
+var checksumExtensions = map[Checksum]string{
+	ChecksumSHA256: "sha256",
+	ChecksumMD5:    "md5",
+}
+
+var ErrWrongChecksum = errors.New("unknown checksum algorithm")
+
+// ChunkChecksum contains checksums of a chunk
+// Path - a path of the chunk file
+// Digests - hex encoded digests of the chunk by algorithms
+type ChunkChecksum struct {
+	Path    string
+	Digests map[Checksum]string
+}
+
+// validateChecksums checks algorithms of checksums
+func (s Splitter) validateChecksums() error {
+	for _, checksum := range s.Checksums {
+		if _, ok := checksumHashes[checksum]; !ok {
+			return ErrWrongChecksum
+		}
+	}
+
+	return nil
+}
+
+// checksumWriter computes checksums of data written to the chunk file
+type checksumWriter struct {
+	io.WriteCloser
+	hashes map[Checksum]hash.Hash
+}
+
+func (w *checksumWriter) Write(data []byte) (int, error) {
+	n, err := w.WriteCloser.Write(data)
+	for _, h := range w.hashes {
+		h.Write(data[:n])
+	}
+
+	return n, err
+}
+
+// trackChecksums wraps the new chunk file, so its checksums are computed while it's written
+func (s Splitter) trackChecksums(st *state, file io.WriteCloser) io.WriteCloser {
+	if len(s.Checksums) == 0 {
+		return file
+	}
+	writer := &checksumWriter{WriteCloser: file, hashes: make(map[Checksum]hash.Hash, len(s.Checksums))}
+	for _, checksum := range s.Checksums {
+		writer.hashes[checksum] = checksumHashes[checksum]()
+	}
+	st.checksum = writer
+
+	return writer
+}
+
+// saveChecksums stores checksums of the completed chunk and writes them to sidecar files
+// when they're enabled and chunks are stored in files
+func (s Splitter) saveChecksums(st *state) error {
+	if st.checksum == nil {
+		return nil
+	}
+	writer := st.checksum
+	st.checksum = nil
+	checksum := ChunkChecksum{Path: st.chunkFilePath, Digests: make(map[Checksum]string, len(writer.hashes))}
+	for algorithm, h := range writer.hashes {
+		checksum.Digests[algorithm] = hex.EncodeToString(h.Sum(nil))
+	}
+	st.checksums = append(st.checksums, checksum)
+	if !s.ChecksumFiles || s.chunkOp != nil {
+		return nil
+	}
+	for _, algorithm := range s.Checksums {
+		path := fmt.Sprintf("%s.%s", checksum.Path, checksumExtensions[algorithm])
+		line := fmt.Sprintf("%s  %s\n", checksum.Digests[algorithm], filepath.Base(checksum.Path))
+		if err := s.writeChecksumFile(path, line); err != nil {
+			return &ChunkWriteError{Op: "write checksum", Path: path, Chunk: st.chunk, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// writeChecksumFile writes the line in the format of sha256sum to the checksum file
+func (s Splitter) writeChecksumFile(path string, line string) error {
+	file, err := s.fileOp.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(file, line); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,91 @@
+package split_csv
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Checksums(t *testing.T) {
+	digests := func(t *testing.T, path string) map[Checksum]string {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		sha := sha256.Sum256(data)
+		md := md5.Sum(data)
+		return map[Checksum]string{
+			ChecksumSHA256: hex.EncodeToString(sha[:]),
+			ChecksumMD5:    hex.EncodeToString(md[:]),
+		}
+	}
+
+	t.Run("It computes checksums of chunks and writes sidecar files", func(t *testing.T) {
+		for _, strict := range []bool{false, true} {
+			s := newTestSplitter(";", 400)
+			s.Checksums = []Checksum{ChecksumSHA256, ChecksumMD5}
+			s.StrictChunkSize = strict
+			s.ChunkTrailer = "TOTAL;%d"
+			s.ChecksumFiles = true
+			result, err := s.SplitWithResult("testdata/test.csv", t.TempDir())
+			require.NoError(t, err)
+
+			require.Len(t, result.Checksums, len(result.Chunks))
+			for i, checksum := range result.Checksums {
+				expected := digests(t, result.Chunks[i])
+				assert.Equal(t, ChunkChecksum{Path: result.Chunks[i], Digests: expected}, checksum)
+				sha, err := os.ReadFile(checksum.Path + ".sha256")
+				require.NoError(t, err)
+				assert.Equal(t, expected[ChecksumSHA256]+"  "+filepath.Base(checksum.Path)+"\n", string(sha))
+				md, err := os.ReadFile(checksum.Path + ".md5")
+				require.NoError(t, err)
+				assert.Equal(t, expected[ChecksumMD5]+"  "+filepath.Base(checksum.Path)+"\n", string(md))
+			}
+		}
+	})
+	t.Run("It doesn't write sidecar files by default", func(t *testing.T) {
+		dir := t.TempDir()
+		s := newTestSplitter(";", 400)
+		s.Checksums = []Checksum{ChecksumSHA256}
+		result, err := s.SplitWithResult("testdata/test.csv", dir)
+		require.NoError(t, err)
+
+		require.Len(t, result.Checksums, len(result.Chunks))
+		assert.Equal(t, digests(t, result.Chunks[0])[ChecksumSHA256], result.Checksums[0].Digests[ChecksumSHA256])
+		assert.NotContains(t, result.Checksums[0].Digests, ChecksumMD5)
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, len(result.Chunks))
+	})
+	t.Run("It predicts checksums in the dry run", func(t *testing.T) {
+		s := newTestSplitter(";", 400)
+		s.Checksums = []Checksum{ChecksumSHA256, ChecksumMD5}
+		result, err := s.SplitWithResult("testdata/test.csv", t.TempDir())
+		require.NoError(t, err)
+
+		dir := t.TempDir()
+		s.DryRun = true
+		s.ChecksumFiles = true
+		predicted, err := s.SplitWithResult("testdata/test.csv", dir)
+		require.NoError(t, err)
+
+		require.Len(t, predicted.Checksums, len(result.Checksums))
+		for i := range result.Checksums {
+			assert.Equal(t, result.Checksums[i].Digests, predicted.Checksums[i].Digests)
+		}
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+	t.Run("It fails on unknown algorithms", func(t *testing.T) {
+		s := newTestSplitter(";", 400)
+		s.Checksums = []Checksum{Checksum(42)}
+		_, err := s.Split("testdata/test.csv", t.TempDir())
+
+		assert.ErrorIs(t, err, ErrWrongChecksum)
+	})
+}
@@ -0,0 +1,106 @@
+package split_csv
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnknownColumn        = errors.New("unknown column")
+	ErrColumnsConflict      = errors.New("columns should be selected either by names or by indexes")
+	ErrColumnsWithoutHeader = errors.New("columns can be selected by names only with header")
+)
+
+// isProjecting checks whether only selected columns should be kept
+func (s Splitter) isProjecting() bool {
+	return len(s.Columns) > 0 || len(s.ColumnIndexes) > 0
+}
+
+// isTransforming checks whether records should be formatted again
+func (s Splitter) isTransforming() bool {
+	return s.isProjecting() || s.Transform != nil || s.isConverting()
+}
+
+// validateColumns checks options of the columns selection
+func (s Splitter) validateColumns() error {
+	if len(s.Columns) > 0 && len(s.ColumnIndexes) > 0 {
+		return ErrColumnsConflict
+	}
+	if len(s.Columns) > 0 && !s.hasHeaderNames() {
+		return ErrColumnsWithoutHeader
+	}
+	for _, index := range s.ColumnIndexes {
+		if index < 0 {
+			return fmt.Errorf("%w: %d", ErrUnknownColumn, index)
+		}
+	}
+
+	return nil
+}
+
+// resolveColumns returns indexes of the selected columns, names are looked up in the header
+func (s Splitter) resolveColumns(st *state) ([]int, error) {
+	if len(s.ColumnIndexes) > 0 {
+		return s.ColumnIndexes, nil
+	}
+
+	return positionsOf(st, s.Columns)
+}
+
+// positionsOf returns indexes of the columns with the given names in the header
+func positionsOf(st *state, names []string) ([]int, error) {
+	positions := st.headerPositions()
+	columns := make([]int, len(names))
+	for i, name := range names {
+		index, ok := positions[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownColumn, name)
+		}
+		columns[i] = index
+	}
+
+	return columns, nil
+}
+
+// projectRecord returns the selected fields of the record resolving the selected columns if needed.
+// The selected columns are checked on the header, or on the first data record when Transform can change
+// the number of fields.
+func (s Splitter) projectRecord(st *state, fields []string, isHeader bool) ([]string, error) {
+	if st.columns == nil {
+		var err error
+		if st.columns, err = s.resolveColumns(st); err != nil {
+			return nil, err
+		}
+	}
+	if !st.columnsChecked && (!isHeader || s.Transform == nil) {
+		if err := checkColumns(st.columns, fields); err != nil {
+			return nil, err
+		}
+		st.columnsChecked = true
+	}
+
+	return projectFields(fields, st.columns), nil
+}
+
+// checkColumns checks that the selected columns exist in the record
+func checkColumns(columns []int, fields []string) error {
+	for _, index := range columns {
+		if index >= len(fields) {
+			return fmt.Errorf("%w: %d", ErrUnknownColumn, index)
+		}
+	}
+
+	return nil
+}
+
+// projectFields returns the selected fields in the selected order, missing fields are empty
+func projectFields(fields []string, columns []int) []string {
+	result := make([]string, len(columns))
+	for i, index := range columns {
+		if index < len(fields) {
+			result[i] = fields[index]
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,74 @@
+package split_csv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SplitColumns_integration(t *testing.T) {
+	t.Run("It selects columns by header names", func(t *testing.T) {
+		s := New()
+		s.Separator = ";"
+		s.FileChunkSize = 200
+		s.Columns = []string{" Test header 5", "Multiline\nheader; with 3\nlines", "Test header 1"}
+		result, err := s.Split("testdata/test_multiline_cells.csv", t.TempDir())
+
+		require.NoError(t, err)
+		require.Greater(t, len(result), 1)
+		content := readChunks(t, result)
+		// fields with leading spaces are quoted by the csv writer
+		header := "\" Test header 5\";\"Multiline\nheader; with 3\nlines\";Test header 1\n"
+		assert.True(t, strings.HasPrefix(content, header+"\" test value\";\" test value\";1\n"))
+		assert.Equal(t, len(result), strings.Count(content, header))
+		assert.Contains(t, content, "\n\"test 3423423 :|\"\" dfadf dfadf, dafad;bnc\nva;lue\";\"\"\"\";\n")
+	})
+	t.Run("It selects columns by indexes", func(t *testing.T) {
+		s := New()
+		s.Separator = ";"
+		s.FileChunkSize = 100
+		s.WithHeader = false
+		s.AllowSmallInput = true
+		s.ColumnIndexes = []int{2, 0}
+		result, err := s.SplitReader(strings.NewReader("a;b;c\n1;2;\"3\n3\"\n4;5\n"), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		assert.Equal(t, "c;a\n\"3\n3\";1\n;4\n", readChunks(t, result))
+	})
+	t.Run("It fails on an unknown column", func(t *testing.T) {
+		s := New()
+		s.Separator = ";"
+		s.FileChunkSize = 100
+		s.AllowSmallInput = true
+		s.Columns = []string{"a", "d"}
+		result, err := s.SplitReader(strings.NewReader("a;b;c\n1;2;3\n"), t.TempDir(), "test")
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrUnknownColumn)
+		assert.EqualError(t, err, "unknown column: d")
+	})
+	t.Run("It fails on an index beyond fields of the first record", func(t *testing.T) {
+		for _, withHeader := range []bool{true, false} {
+			s := newTestSplitter(";", 100)
+			s.WithHeader = withHeader
+			s.ColumnIndexes = []int{0, 3}
+			result, err := s.SplitReader(strings.NewReader("a;b;c\n1;2;3\n"), t.TempDir(), "test")
+
+			assert.Nil(t, result)
+			assert.ErrorIs(t, err, ErrUnknownColumn)
+			assert.ErrorContains(t, err, "unknown column: 3")
+		}
+	})
+	t.Run("It fails when columns are selected by names and indexes", func(t *testing.T) {
+		s := New()
+		s.FileChunkSize = 100
+		s.Columns = []string{"a"}
+		s.ColumnIndexes = []int{1}
+		result, err := s.SplitReader(strings.NewReader("a,b\n"), t.TempDir(), "test")
+
+		assert.Nil(t, result)
+		assert.Equal(t, ErrColumnsConflict, err)
+	})
+}
@@ -0,0 +1,139 @@
+package split_csv
+
+// ChunkedCSVWriter writes records to chunk files like encoding/csv Writer does to one file.
+// The first record is the header when WithHeader is set, it's copied to every chunk.
+// Records go through filters, transformations and other options of the splitter.
+// A chunk is completed when it reaches FileChunkSize, in the strict mode it never exceeds it.
+type ChunkedCSVWriter struct {
+	s      Splitter
+	st     *state
+	result *Result
+	err    error
+}
+
+// NewChunkedCSVWriter initializes the writer of records to chunks in the output directory
+func (s Splitter) NewChunkedCSVWriter(outputDirPath string, outputFilePrefix string) (*ChunkedCSVWriter, error) {
+	if s.Parts > 0 {
+		return nil, ErrPartsNotSupported
+	}
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	if s.inputFormat() == FormatNDJSON {
+		return nil, ErrFieldsNotSupported
+	}
+	s.rolloverOnly = true
+	s = s.withDryRun()
+	st, err := s.start(outputDirPath, outputFilePrefix, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChunkedCSVWriter{s: s, st: st}, nil
+}
+
+// Write writes the record to the current chunk, completing the chunk before it if it has reached FileChunkSize
+func (w *ChunkedCSVWriter) Write(record []string) error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.result != nil {
+		return ErrWriterClosed
+	}
+	if err := w.write(record); err != nil {
+		w.fail(err)
+		return err
+	}
+
+	return nil
+}
+
+// WriteAll writes the records and flushes them to the chunk files
+func (w *ChunkedCSVWriter) WriteAll(records [][]string) error {
+	for _, record := range records {
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+
+	return w.Error()
+}
+
+// Flush writes buffered records to the current chunk file, Error should be used to check whether it failed
+func (w *ChunkedCSVWriter) Flush() {
+	if w.err != nil || w.result != nil || w.st.bulkBuffer.Len() == 0 {
+		return
+	}
+	if err := w.s.saveBulkToFile(w.st); err != nil {
+		w.fail(err)
+	}
+}
+
+// Error returns an error which has occurred during a previous Write or Flush
+func (w *ChunkedCSVWriter) Error() error {
+	return w.err
+}
+
+// Close flushes buffered records and completes the last chunk
+func (w *ChunkedCSVWriter) Close() error {
+	if w.err != nil || w.result != nil {
+		return w.err
+	}
+	defer w.st.closeDedup()
+	result, err := w.s.finish(w.st, nil)
+	if err != nil {
+		w.fail(err)
+		return err
+	}
+	w.result = result
+
+	return nil
+}
+
+// Chunks returns paths of the created chunk files, it's nil until the writer is closed
+func (w *ChunkedCSVWriter) Chunks() []string {
+	if w.result == nil {
+		return nil
+	}
+
+	return w.result.Chunks
+}
+
+// Result returns details of the split, it's nil until the writer is closed
+func (w *ChunkedCSVWriter) Result() *Result {
+	return w.result
+}
+
+// write formats the record and handles it like a record of the input
+func (w *ChunkedCSVWriter) write(fields []string) error {
+	st := w.st
+	if !w.s.StrictChunkSize && !(st.isFirstLine && w.s.hasHeader()) {
+		size := st.chunkSize
+		if size == 0 {
+			size = int64(len(st.header))
+		}
+		if st.chunkFile != nil && size+int64(st.bulkBuffer.Len()) >= int64(w.s.FileChunkSize) {
+			st.rollover = true
+			if err := w.s.saveBulkToFile(st); err != nil {
+				return err
+			}
+		}
+	}
+	record := w.s.formatInput(fields)
+	st.line++
+	st.recordLine = st.line
+	st.lineOffset += int64(len(record))
+
+	return w.s.handleRecord(st, record, append([]string{}, fields...))
+}
+
+// fail stops writing with the error
+func (w *ChunkedCSVWriter) fail(err error) {
+	w.err = err
+	w.st.closeDedup()
+	w.st.closeRejects()
+	if w.st.chunkFile != nil {
+		w.st.chunkFile.Close()
+	}
+}
@@ -0,0 +1,85 @@
+package split_csv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ChunkedCSVWriter_integration(t *testing.T) {
+	records := [][]string{{"id", "comment"}}
+	for i := 1; i <= 20; i++ {
+		records = append(records, []string{fmt.Sprint(i), fmt.Sprintf("multi\nline, \"%d\"", i)})
+	}
+	var expected bytes.Buffer
+	csvWriter := csv.NewWriter(&expected)
+	require.NoError(t, csvWriter.WriteAll(records[1:]))
+	header := "id,comment\n"
+	readAll := func(t *testing.T, chunks []string) string {
+		var content string
+		for _, chunk := range chunks {
+			data, err := os.ReadFile(chunk)
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(string(data), header))
+			content += strings.TrimPrefix(string(data), header)
+		}
+		return content
+	}
+
+	t.Run("It writes records to chunks with header", func(t *testing.T) {
+		dir := t.TempDir()
+		w, err := newTestSplitter(",", 100).NewChunkedCSVWriter(dir, "test")
+		require.NoError(t, err)
+		for _, record := range records {
+			require.NoError(t, w.Write(record))
+		}
+		w.Flush()
+		require.NoError(t, w.Error())
+		require.NoError(t, w.Close())
+
+		require.Greater(t, len(w.Chunks()), 1)
+		assert.Equal(t, filepath.Join(dir, "test_1.csv"), w.Chunks()[0])
+		assert.Equal(t, expected.String(), readAll(t, w.Chunks()))
+	})
+	t.Run("It doesn't exceed the chunk size in the strict mode", func(t *testing.T) {
+		s := newTestSplitter(",", 100)
+		s.StrictChunkSize = true
+		w, err := s.NewChunkedCSVWriter(t.TempDir(), "test")
+		require.NoError(t, err)
+		require.NoError(t, w.WriteAll(records))
+		require.NoError(t, w.Close())
+
+		for _, chunk := range w.Chunks() {
+			stat, err := os.Stat(chunk)
+			require.NoError(t, err)
+			assert.LessOrEqual(t, stat.Size(), int64(s.FileChunkSize))
+		}
+		assert.Equal(t, expected.String(), readAll(t, w.Chunks()))
+	})
+	t.Run("It applies options of the splitter", func(t *testing.T) {
+		s := newTestSplitter(",", 100)
+		s.Columns = []string{"id"}
+		s.Filters = []RowFilter{{Column: "id", Op: FilterMatches, Value: "^1"}}
+		w, err := s.NewChunkedCSVWriter(t.TempDir(), "test")
+		require.NoError(t, err)
+		require.NoError(t, w.WriteAll(records))
+		require.NoError(t, w.Close())
+
+		assert.Equal(t, "id\n1\n10\n11\n12\n13\n14\n15\n16\n17\n18\n19\n", readChunks(t, w.Chunks()))
+		assert.Equal(t, 9, w.Result().DroppedRows)
+	})
+	t.Run("It doesn't accept records after Close", func(t *testing.T) {
+		w, err := newTestSplitter(",", 100).NewChunkedCSVWriter(t.TempDir(), "test")
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		assert.ErrorIs(t, w.Write(records[0]), ErrWriterClosed)
+	})
+}
@@ -0,0 +1,164 @@
+package split_csv
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrNotStruct = errors.New("records can be decoded only into structs")
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// fieldDecoder decodes a field of the record into a field of the struct
+type fieldDecoder struct {
+	index  []int  // index of the struct field
+	column int    // index of the record field
+	name   string // name of the column
+	layout string // layout of time fields
+}
+
+// SplitInto decodes records of the source into structs and passes them in batches to the function.
+// Columns are mapped to struct fields by names in `csv:"name"` tags or by field names,
+// fields are mapped by their order when the input has no header. A tag "-" skips the field.
+// Strings, integers, floats, bools, time.Time (RFC 3339 or a layout given as `csv:"name,layout=2006-01-02"`),
+// encoding.TextUnmarshaler and pointers to them are supported, empty fields are decoded as zero values.
+func SplitInto[T any](s Splitter, source io.Reader, batchSize int, fn func(batch []T) error) error {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		return ErrNotStruct
+	}
+	var decoders []fieldDecoder
+
+	return s.ForEachBatch(source, batchSize, 1, func(batch Batch) error {
+		if decoders == nil {
+			var err error
+			if decoders, err = newFieldDecoders(typ, batch.Header); err != nil {
+				return err
+			}
+		}
+		items := make([]T, len(batch.Records))
+		for i, record := range batch.Records {
+			value := reflect.ValueOf(&items[i]).Elem()
+			for _, decoder := range decoders {
+				if err := decoder.decode(value, record); err != nil {
+					return &DecodeError{Line: batch.Lines[i], Column: decoder.name, Err: err}
+				}
+			}
+		}
+
+		return fn(items)
+	})
+}
+
+// newFieldDecoders maps columns of the header to fields of the struct
+func newFieldDecoders(typ reflect.Type, header []string) ([]fieldDecoder, error) {
+	positions := make(map[string]int, len(header))
+	for i := len(header) - 1; i >= 0; i-- {
+		positions[header[i]] = i
+	}
+	var decoders []fieldDecoder
+	for _, field := range reflect.VisibleFields(typ) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		tag, options, _ := strings.Cut(field.Tag.Get("csv"), ",")
+		if tag == "-" {
+			continue
+		}
+		decoder := fieldDecoder{index: field.Index, name: tag, layout: time.RFC3339}
+		if decoder.name == "" {
+			decoder.name = field.Name
+		}
+		if layout, ok := strings.CutPrefix(options, "layout="); ok {
+			decoder.layout = layout
+		}
+		decoder.column = len(decoders)
+		if header != nil {
+			column, ok := positions[decoder.name]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownColumn, decoder.name)
+			}
+			decoder.column = column
+		}
+		decoders = append(decoders, decoder)
+	}
+
+	return decoders, nil
+}
+
+// decode sets the struct field from the record
+func (d fieldDecoder) decode(value reflect.Value, record []string) error {
+	field := ""
+	if d.column < len(record) {
+		field = record[d.column]
+	}
+
+	return decodeValue(value.FieldByIndex(d.index), field, d.layout)
+}
+
+// decodeValue converts the field to the type of the value
+func decodeValue(value reflect.Value, field string, layout string) error {
+	if field == "" {
+		value.SetZero()
+		return nil
+	}
+	switch {
+	case value.Kind() == reflect.Pointer:
+		elem := reflect.New(value.Type().Elem())
+		if err := decodeValue(elem.Elem(), field, layout); err != nil {
+			return err
+		}
+		value.Set(elem)
+		return nil
+	case value.Type() == timeType:
+		t, err := time.Parse(layout, field)
+		if err != nil {
+			return err
+		}
+		value.Set(reflect.ValueOf(t))
+		return nil
+	case reflect.PointerTo(value.Type()).Implements(textUnmarshalerType):
+		return value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(field))
+	}
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(field)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(field)
+		if err != nil {
+			return err
+		}
+		value.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(field, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(field, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(field, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(v)
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package split_csv
+
+import (
+	"errors"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type decodedRecord struct {
+	ID      int64      `csv:"id"`
+	Name    string     `csv:"name"`
+	Score   float64    `csv:"score"`
+	Active  bool       `csv:"active"`
+	Born    time.Time  `csv:"born,layout=2006-01-02"`
+	Address netip.Addr `csv:"address"`
+	Parent  *uint8     `csv:"parent"`
+	Skipped string     `csv:"-"`
+}
+
+func Test_SplitInto_integration(t *testing.T) {
+	input := "name,id,score,active,born,address,parent,extra\n" +
+		"\"Smith,\nJohn\",1,1.5,true,2000-01-02,127.0.0.1,,x\n" +
+		"Jane,2,,false,,::1,1,y\n" +
+		"Jim,3,3,1,2001-02-03,10.0.0.1,2,z\n"
+	parent := func(v uint8) *uint8 {
+		return &v
+	}
+
+	t.Run("It decodes records into structs", func(t *testing.T) {
+		var batches [][]decodedRecord
+		err := SplitInto(New(), strings.NewReader(input), 2, func(batch []decodedRecord) error {
+			batches = append(batches, batch)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]decodedRecord{
+			{
+				{
+					ID:      1,
+					Name:    "Smith,\nJohn",
+					Score:   1.5,
+					Active:  true,
+					Born:    time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC),
+					Address: netip.MustParseAddr("127.0.0.1"),
+				},
+				{ID: 2, Name: "Jane", Address: netip.MustParseAddr("::1"), Parent: parent(1)},
+			},
+			{
+				{
+					ID:      3,
+					Name:    "Jim",
+					Score:   3,
+					Active:  true,
+					Born:    time.Date(2001, 2, 3, 0, 0, 0, 0, time.UTC),
+					Address: netip.MustParseAddr("10.0.0.1"),
+					Parent:  parent(2),
+				},
+			},
+		}, batches)
+	})
+	t.Run("It maps fields by order without header", func(t *testing.T) {
+		type record struct {
+			Name string
+			ID   int
+		}
+		s := New()
+		s.WithHeader = false
+		var records []record
+		err := SplitInto(s, strings.NewReader("a,1\nb,2\n"), 10, func(batch []record) error {
+			records = append(records, batch...)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []record{{"a", 1}, {"b", 2}}, records)
+	})
+	t.Run("It reports the line of a wrong field", func(t *testing.T) {
+		wrongInput := strings.Replace(input, "Jim,3", "Jim,three", 1)
+		err := SplitInto(New(), strings.NewReader(wrongInput), 10, func(batch []decodedRecord) error {
+			return nil
+		})
+
+		var decodeErr *DecodeError
+		require.True(t, errors.As(err, &decodeErr))
+		assert.Equal(t, 5, decodeErr.Line)
+		assert.Equal(t, "id", decodeErr.Column)
+		assert.ErrorIs(t, err, strconv.ErrSyntax)
+	})
+	t.Run("It fails on unknown columns", func(t *testing.T) {
+		type record struct {
+			Unknown string `csv:"unknown"`
+		}
+		err := SplitInto(New(), strings.NewReader(input), 10, func(batch []record) error {
+			return nil
+		})
+
+		assert.ErrorIs(t, err, ErrUnknownColumn)
+	})
+	t.Run("It decodes only into structs", func(t *testing.T) {
+		err := SplitInto(New(), strings.NewReader(input), 10, func(batch []string) error {
+			return nil
+		})
+
+		assert.ErrorIs(t, err, ErrNotStruct)
+	})
+}
@@ -0,0 +1,332 @@
+package split_csv
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// digestSize is a size of keys digests kept in the deduplication set
+const digestSize = 16
+
+type digest [digestSize]byte
+
+var ErrDedupNotResumable = errors.New("duplicates can't be removed with checkpoints")
+
+// Options of spill runs
+const (
+	// dedupBlockKeys is a number of digests in a block of a run which is read by one lookup
+	dedupBlockKeys = 256
+	// dedupMergeRuns is a number of runs of the same level which are merged into one run of the next level
+	dedupMergeRuns = 8
+	// bloomBitsPerKey and bloomHashes give about 1% of false positives of run filters
+	bloomBitsPerKey = 10
+	bloomHashes     = 7
+)
+
+// dedupSet remembers digests of the seen keys. Digests are kept in memory until their number reaches
+// maxKeys, then they are written to a sorted run in a spill file. Every dedupMergeRuns runs of the same
+// level are merged into one run, so every digest is rewritten a logarithmic number of times.
+type dedupSet struct {
+	keys    map[digest]struct{}
+	maxKeys int // unlimited when 0
+	dir     string
+	runs    []*dedupRun
+	block   []byte
+}
+
+// dedupRun is a sorted run of digests in a spill file. Its bloom filter and the first digests
+// of its blocks are kept in memory, so a lookup reads at most one block of the file.
+type dedupRun struct {
+	file   *os.File
+	keys   int64
+	level  int
+	filter bloomFilter
+	index  []digest
+}
+
+// bloomFilter checks whether a digest could be added to the run
+type bloomFilter []uint64
+
+// newDedupSet initializes the deduplication set
+func newDedupSet(maxKeys int, dir string) *dedupSet {
+	return &dedupSet{
+		keys:    make(map[digest]struct{}),
+		maxKeys: maxKeys,
+		dir:     dir,
+	}
+}
+
+// add remembers the key and checks whether it has been seen before
+func (d *dedupSet) add(key []byte) (bool, error) {
+	var k digest
+	sum := sha256.Sum256(key)
+	copy(k[:], sum[:])
+	if _, ok := d.keys[k]; ok {
+		return true, nil
+	}
+	for _, run := range d.runs {
+		seen, err := d.isInRun(run, k)
+		if err != nil {
+			return false, fmt.Errorf("Couldn't read deduplication spill file: %w", err)
+		}
+		if seen {
+			return true, nil
+		}
+	}
+	d.keys[k] = struct{}{}
+	if d.maxKeys > 0 && len(d.keys) >= d.maxKeys {
+		return false, d.flush()
+	}
+
+	return false, nil
+}
+
+// isInRun searches the digest in the block of the run where it would be
+func (d *dedupSet) isInRun(run *dedupRun, k digest) (bool, error) {
+	if !run.filter.has(k) {
+		return false, nil
+	}
+	block := sort.Search(len(run.index), func(i int) bool {
+		return bytes.Compare(run.index[i][:], k[:]) > 0
+	}) - 1
+	if block < 0 {
+		return false, nil
+	}
+	start := int64(block) * dedupBlockKeys
+	count := int(min(run.keys-start, dedupBlockKeys))
+	if d.block == nil {
+		d.block = make([]byte, dedupBlockKeys*digestSize)
+	}
+	keys := d.block[:count*digestSize]
+	if _, err := run.file.ReadAt(keys, start*digestSize); err != nil {
+		return false, err
+	}
+	i := sort.Search(count, func(i int) bool {
+		return bytes.Compare(keys[i*digestSize:(i+1)*digestSize], k[:]) >= 0
+	})
+
+	return i < count && bytes.Equal(keys[i*digestSize:(i+1)*digestSize], k[:]), nil
+}
+
+// flush writes digests kept in memory to a new run and merges runs of the same level
+func (d *dedupSet) flush() error {
+	keys := make([]digest, 0, len(d.keys))
+	for k := range d.keys {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i][:], keys[j][:]) < 0
+	})
+	run, err := d.writeRun(int64(len(keys)), 0, func() (digest, bool, error) {
+		if len(keys) == 0 {
+			return digest{}, false, nil
+		}
+		k := keys[0]
+		keys = keys[1:]
+		return k, true, nil
+	})
+	if err != nil {
+		return err
+	}
+	d.runs = append(d.runs, run)
+	d.keys = make(map[digest]struct{})
+
+	return d.compact()
+}
+
+// compact merges the last runs while dedupMergeRuns of them have the same level.
+// Runs are appended at level 0, so their levels don't grow to the end of the list.
+func (d *dedupSet) compact() error {
+	for len(d.runs) >= dedupMergeRuns {
+		tail := d.runs[len(d.runs)-dedupMergeRuns:]
+		if tail[0].level != tail[len(tail)-1].level {
+			return nil
+		}
+		run, err := d.merge(tail)
+		if err != nil {
+			return err
+		}
+		for _, merged := range tail {
+			merged.close()
+		}
+		d.runs = append(d.runs[:len(d.runs)-dedupMergeRuns], run)
+	}
+
+	return nil
+}
+
+// merge writes sorted digests of the runs to a new run of the next level
+func (d *dedupSet) merge(runs []*dedupRun) (*dedupRun, error) {
+	readers := make([]*bufio.Reader, len(runs))
+	heads := make([]digest, len(runs))
+	hasHead := make([]bool, len(runs))
+	read := func(i int) error {
+		_, err := io.ReadFull(readers[i], heads[i][:])
+		if errors.Is(err, io.EOF) {
+			hasHead[i] = false
+			return nil
+		}
+		hasHead[i] = err == nil
+		return err
+	}
+	var keys int64
+	for i, run := range runs {
+		keys += run.keys
+		readers[i] = bufio.NewReader(io.NewSectionReader(run.file, 0, run.keys*digestSize))
+		if err := read(i); err != nil {
+			return nil, fmt.Errorf("Couldn't read deduplication spill file: %w", err)
+		}
+	}
+
+	return d.writeRun(keys, runs[0].level+1, func() (digest, bool, error) {
+		least := -1
+		for i := range runs {
+			if hasHead[i] && (least < 0 || bytes.Compare(heads[i][:], heads[least][:]) < 0) {
+				least = i
+			}
+		}
+		if least < 0 {
+			return digest{}, false, nil
+		}
+		k := heads[least]
+		return k, true, read(least)
+	})
+}
+
+// writeRun writes the given number of sorted digests returned by next to a new spill file
+func (d *dedupSet) writeRun(keys int64, level int, next func() (digest, bool, error)) (*dedupRun, error) {
+	file, err := os.CreateTemp(d.dir, "split-csv-dedup-*")
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't create deduplication spill file: %w", err)
+	}
+	run := &dedupRun{file: file, keys: keys, level: level, filter: newBloomFilter(keys)}
+	if err := run.write(next); err != nil {
+		run.close()
+		return nil, fmt.Errorf("Couldn't write deduplication spill file: %w", err)
+	}
+
+	return run, nil
+}
+
+// write writes digests to the file of the run filling its filter and index
+func (r *dedupRun) write(next func() (digest, bool, error)) error {
+	w := bufio.NewWriter(r.file)
+	for i := 0; ; i++ {
+		k, ok, err := next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		if i%dedupBlockKeys == 0 {
+			r.index = append(r.index, k)
+		}
+		r.filter.add(k)
+		if _, err := w.Write(k[:]); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// close removes the file of the run
+func (r *dedupRun) close() {
+	r.file.Close()
+	os.Remove(r.file.Name())
+}
+
+// close removes spill files
+func (d *dedupSet) close() {
+	for _, run := range d.runs {
+		run.close()
+	}
+	d.runs = nil
+}
+
+// newBloomFilter creates a filter for the given number of digests
+func newBloomFilter(keys int64) bloomFilter {
+	return make(bloomFilter, (keys*bloomBitsPerKey+63)/64+1)
+}
+
+// bit returns the word and the mask of the i-th bit of the digest.
+// Digests are uniformly distributed, so their halves are used as hashes.
+func (f bloomFilter) bit(k digest, i int) (int, uint64) {
+	h1 := binary.LittleEndian.Uint64(k[:8])
+	h2 := binary.LittleEndian.Uint64(k[8:]) | 1
+	pos := (h1 + uint64(i)*h2) % uint64(len(f)*64)
+
+	return int(pos / 64), 1 << (pos % 64)
+}
+
+func (f bloomFilter) add(k digest) {
+	for i := 0; i < bloomHashes; i++ {
+		word, mask := f.bit(k, i)
+		f[word] |= mask
+	}
+}
+
+func (f bloomFilter) has(k digest) bool {
+	for i := 0; i < bloomHashes; i++ {
+		if word, mask := f.bit(k, i); f[word]&mask == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isDeduplicating checks whether duplicated records should be removed
+func (s Splitter) isDeduplicating() bool {
+	return s.Dedup
+}
+
+// validateDedup checks options of the deduplication
+func (s Splitter) validateDedup() error {
+	if len(s.DedupColumns) > 0 && !s.hasHeaderNames() {
+		return ErrColumnsWithoutHeader
+	}
+	if s.isDeduplicating() && s.CheckpointPath != "" {
+		return ErrDedupNotResumable
+	}
+
+	return nil
+}
+
+// dedupKey returns the key identifying the record by fields of DedupColumns
+func (s Splitter) dedupKey(st *state, fields []string) ([]byte, error) {
+	if st.dedupColumns == nil {
+		var err error
+		if st.dedupColumns, err = positionsOf(st, s.DedupColumns); err != nil {
+			return nil, err
+		}
+	}
+	var key []byte
+	for _, field := range projectFields(fields, st.dedupColumns) {
+		key = binary.AppendUvarint(key, uint64(len(field)))
+		key = append(key, field...)
+	}
+
+	return key, nil
+}
+
+// isDuplicate checks whether the record with the given key has been already written
+func (s Splitter) isDuplicate(st *state, key []byte) (bool, error) {
+	if st.dedup == nil {
+		st.dedup = newDedupSet(s.DedupMaxKeys, s.DedupSpillDir)
+	}
+	seen, err := st.dedup.add(key)
+	if seen {
+		st.duplicates++
+	}
+
+	return seen, err
+}
@@ -0,0 +1,119 @@
+package split_csv
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dedupInput = `id,name,comment
+1,John,"first
+line"
+2,Jane,second
+1,John,"first
+line"
+3,John,third
+2,Jane,second`
+
+func Test_SplitDedup_integration(t *testing.T) {
+	t.Run("It removes duplicated records", func(t *testing.T) {
+		s := newTestSplitter(",", 100)
+		s.Dedup = true
+		result, err := s.SplitReaderWithResult(strings.NewReader(dedupInput), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			"id,name,comment\n1,John,\"first\nline\"\n2,Jane,second\n3,John,third\n",
+			readChunks(t, result.Chunks),
+		)
+		assert.Equal(t, 2, result.DuplicateRows)
+	})
+	t.Run("It removes records with duplicated columns", func(t *testing.T) {
+		s := newTestSplitter(",", 100)
+		s.Dedup = true
+		s.DedupColumns = []string{"name"}
+		result, err := s.SplitReaderWithResult(strings.NewReader(dedupInput), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			"id,name,comment\n1,John,\"first\nline\"\n2,Jane,second\n",
+			readChunks(t, result.Chunks),
+		)
+		assert.Equal(t, 3, result.DuplicateRows)
+	})
+	t.Run("It spills keys to a file", func(t *testing.T) {
+		var input strings.Builder
+		var expected strings.Builder
+		input.WriteString("id,value\n")
+		for i := 0; i < 100; i++ {
+			fmt.Fprintf(&input, "%d,value\n", i%40)
+			if i < 40 {
+				fmt.Fprintf(&expected, "%d,value\n", i)
+			}
+		}
+		spillDir := t.TempDir()
+		s := newTestSplitter(",", 100)
+		s.Dedup = true
+		s.DedupMaxKeys = 7
+		s.DedupSpillDir = spillDir
+		result, err := s.SplitReaderWithResult(strings.NewReader(input.String()), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		content := readChunks(t, result.Chunks)
+		assert.Equal(t, expected.String(), strings.ReplaceAll(content, "id,value\n", ""))
+		assert.Equal(t, 60, result.DuplicateRows)
+		files, err := os.ReadDir(spillDir)
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	})
+	t.Run("It requires header for columns", func(t *testing.T) {
+		s := newTestSplitter(",", 100)
+		s.Dedup = true
+		s.WithHeader = false
+		s.DedupColumns = []string{"name"}
+		_, err := s.SplitReader(strings.NewReader(dedupInput), t.TempDir(), "test")
+
+		assert.ErrorIs(t, err, ErrColumnsWithoutHeader)
+	})
+	t.Run("It doesn't remove duplicates with checkpoints", func(t *testing.T) {
+		s := newTestSplitter(",", 100)
+		s.Dedup = true
+		s.CheckpointPath = filepath.Join(t.TempDir(), "checkpoint.json")
+		_, err := s.SplitReader(strings.NewReader(dedupInput), t.TempDir(), "test")
+
+		assert.ErrorIs(t, err, ErrDedupNotResumable)
+	})
+}
+
+func Test_dedupSet(t *testing.T) {
+	dir := t.TempDir()
+	set := newDedupSet(3, dir)
+	defer set.close()
+	for i := 0; i < 2000; i++ {
+		seen, err := set.add([]byte(fmt.Sprint(i)))
+		require.NoError(t, err)
+		require.False(t, seen, i)
+	}
+	// runs are merged, so their number grows logarithmically
+	assert.Less(t, len(set.runs), 3*dedupMergeRuns)
+	for i := 0; i < 2000; i++ {
+		seen, err := set.add([]byte(fmt.Sprint(i)))
+		require.NoError(t, err)
+		require.True(t, seen, i)
+	}
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, len(set.runs))
+
+	set.close()
+	files, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
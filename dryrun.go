@@ -0,0 +1,121 @@
+package split_csv
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// maxWarnings is a max number of malformed records reported in the dry run
+const maxWarnings = 100
+
+// ChunkStat describes a chunk which would be created by the split
+// Path - a path of the chunk file
+// Size - a size of the chunk in bytes including the header and the trailer
+// Rows - a number of data rows in the chunk
+type ChunkStat struct {
+	Path string
+	Size int64
+	Rows int
+}
+
+// withDryRun replaces the operator of chunk files with the discarding one in the dry run
+func (s Splitter) withDryRun() Splitter {
+	if s.DryRun {
+		s.chunkOp = &dryRunOp{sizes: make(map[string]int64)}
+	}
+
+	return s
+}
+
+// chunkStats returns sizes and row counts of the chunks
+func (s Splitter) chunkStats(st *state) []ChunkStat {
+	stats := make([]ChunkStat, 0, len(st.result))
+	for i, path := range st.result {
+		stat := ChunkStat{Path: path, Rows: st.chunkRecords}
+		if i < len(st.chunkRows) {
+			stat.Rows = st.chunkRows[i]
+		}
+		if info, err := s.chunkOperator().Stat(path); err == nil {
+			stat.Size = info.Size()
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats
+}
+
+// warn reports the malformed record in the dry run
+func (st *state) warn(err *ParseError) {
+	if len(st.warnings) < maxWarnings {
+		st.warnings = append(st.warnings, err)
+	}
+}
+
+// countRecords counts records completed by lines of the data which is written to the chunk without reading lines
+func (s Splitter) countRecords(st *state, data []byte) int {
+	count := 0
+	isHeader := st.isFirstLine && s.hasHeader()
+	for _, line := range bytes.SplitAfter(data, []byte{'\n'}) {
+		if len(line) == 0 || isBrokenMultiLine(line, st) {
+			continue
+		}
+		if isHeader {
+			isHeader = false
+			continue
+		}
+		count++
+	}
+
+	return count
+}
+
+// dryRunOp discards chunks counting their sizes
+type dryRunOp struct {
+	sizes map[string]int64
+}
+
+func (d *dryRunOp) Open(name string) (io.ReadCloser, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (d *dryRunOp) Create(name string) (io.WriteCloser, error) {
+	d.sizes[name] = 0
+
+	return &dryRunChunk{op: d, name: name}, nil
+}
+
+func (d *dryRunOp) Stat(name string) (os.FileInfo, error) {
+	size, ok := d.sizes[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return chunkInfo{name: name, size: size}, nil
+}
+
+func (d *dryRunOp) Rename(oldpath string, newpath string) error {
+	return &fs.PathError{Op: "rename", Path: oldpath, Err: fs.ErrNotExist}
+}
+
+func (d *dryRunOp) IsNotExist(err error) bool {
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// dryRunChunk counts bytes written to the chunk
+type dryRunChunk struct {
+	op   *dryRunOp
+	name string
+}
+
+func (c *dryRunChunk) Write(data []byte) (int, error) {
+	c.op.sizes[c.name] += int64(len(data))
+
+	return len(data), nil
+}
+
+func (c *dryRunChunk) Close() error {
+	return nil
+}
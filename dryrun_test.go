@@ -0,0 +1,126 @@
+package split_csv
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DryRun(t *testing.T) {
+	t.Run("It predicts chunks of the split without writing them", func(t *testing.T) {
+		for _, strict := range []bool{false, true} {
+			s := New()
+			s.Separator = ";"
+			s.FileChunkSize = 400
+			s.StrictChunkSize = strict
+			files, err := s.Split("testdata/test.csv", t.TempDir())
+			require.NoError(t, err)
+			var expected []ChunkStat
+			for _, file := range files {
+				data, err := os.ReadFile(file)
+				require.NoError(t, err)
+				rows := strings.Count(strings.TrimSuffix(string(data), "\n"), "\n")
+				expected = append(expected, ChunkStat{Size: int64(len(data)), Rows: rows})
+			}
+
+			dir := t.TempDir()
+			s.DryRun = true
+			result, err := s.SplitWithResult("testdata/test.csv", dir)
+			require.NoError(t, err)
+
+			require.Len(t, result.ChunkStats, len(expected))
+			for i, stat := range result.ChunkStats {
+				assert.Equal(t, result.Chunks[i], stat.Path)
+				assert.Equal(t, expected[i].Size, stat.Size)
+				assert.Equal(t, expected[i].Rows, stat.Rows)
+				assert.NoFileExists(t, stat.Path)
+			}
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+		}
+	})
+	t.Run("It predicts chunks of the split of malformed records", func(t *testing.T) {
+		input := malformedInput + strings.Repeat("7;seventh;70\n", 20)
+		for _, policy := range []MalformedPolicy{MalformedIgnore, MalformedSkip, MalformedReject} {
+			s := newTestSplitter(";", 100)
+			s.Malformed = policy
+			files, err := s.SplitReader(strings.NewReader(input), t.TempDir(), "test")
+			require.NoError(t, err)
+			var expected []ChunkStat
+			for _, file := range files {
+				data, err := os.ReadFile(file)
+				require.NoError(t, err)
+				// every line is a row except the header and the second line of the multiline record
+				rows := strings.Count(string(data), "\n") - 1 - strings.Count(string(data), "\"fifth\n")
+				expected = append(expected, ChunkStat{Size: int64(len(data)), Rows: rows})
+			}
+
+			s.DryRun = true
+			result, err := s.SplitReaderWithResult(strings.NewReader(input), t.TempDir(), "test")
+			require.NoError(t, err)
+
+			require.Greater(t, len(expected), 1)
+			require.Len(t, result.ChunkStats, len(expected), policy)
+			for i, stat := range result.ChunkStats {
+				assert.Equal(t, expected[i].Size, stat.Size, policy)
+				assert.Equal(t, expected[i].Rows, stat.Rows, policy)
+			}
+			assert.Len(t, result.Warnings, 2, policy)
+		}
+	})
+	t.Run("It keeps malformed records reporting them as warnings when they're ignored", func(t *testing.T) {
+		s := newTestSplitter(";", 100)
+		s.DryRun = true
+
+		result, err := s.SplitReaderWithResult(strings.NewReader(malformedInput), t.TempDir(), "test")
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, result.RejectedRows)
+		require.Len(t, result.Warnings, 2)
+		var parseErr *ParseError
+		require.True(t, errors.As(result.Warnings[0], &parseErr))
+		assert.Equal(t, 3, parseErr.Line)
+		assert.ErrorIs(t, result.Warnings[1], ErrUnbalancedQuotes)
+		assert.Equal(t, []ChunkStat{{Path: result.Chunks[0], Size: 96, Rows: 6}}, result.ChunkStats)
+	})
+	t.Run("It skips malformed records reporting them as warnings", func(t *testing.T) {
+		for _, policy := range []MalformedPolicy{MalformedSkip, MalformedReject} {
+			dir := t.TempDir()
+			s := newTestSplitter(";", 100)
+			s.Malformed = policy
+			s.DryRun = true
+
+			result, err := s.SplitReaderWithResult(strings.NewReader(malformedInput), dir, "test")
+			require.NoError(t, err)
+
+			assert.Equal(t, 2, result.RejectedRows)
+			require.Len(t, result.Warnings, 2)
+			var parseErr *ParseError
+			require.True(t, errors.As(result.Warnings[0], &parseErr))
+			assert.Equal(t, 3, parseErr.Line)
+			assert.ErrorIs(t, result.Warnings[1], ErrUnbalancedQuotes)
+			assert.Equal(t, []ChunkStat{{Path: result.Chunks[0], Size: 66, Rows: 4}}, result.ChunkStats)
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+		}
+	})
+	t.Run("It reports the line where the split would stop", func(t *testing.T) {
+		s := newTestSplitter(";", 100)
+		s.Malformed = MalformedFail
+		s.DryRun = true
+
+		result, err := s.SplitReaderWithResult(strings.NewReader(malformedInput), t.TempDir(), "test")
+
+		assert.Nil(t, result)
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr))
+		assert.Equal(t, 3, parseErr.Line)
+		assert.ErrorIs(t, err, ErrFieldsCount)
+	})
+}
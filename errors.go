@@ -0,0 +1,197 @@
+package split_csv
+
+import (
+	"fmt"
+)
+
+// InputError is returned when the input can't be accessed or read
+// Op - an operation which failed: "stat", "open", "seek" or "read"
+// Path - a path of the input file, it's empty for readers
+// Offset - a position in the input where the operation failed
+type InputError struct {
+	Op     string
+	Path   string
+	Offset int64
+	Err    error
+}
+
+func (e *InputError) Error() string {
+	switch e.Op {
+	case "stat":
+		return fmt.Sprintf("Couldn't get file stat %s : %v", e.Path, e.Err)
+	case "open":
+		return fmt.Sprintf("Couldn't open file %s : %v", e.Path, e.Err)
+	case "seek":
+		return fmt.Sprintf("Couldn't seek input to offset %d: %v", e.Offset, e.Err)
+	default:
+		return fmt.Sprintf("Couldn't read file bulk: %v", e.Err)
+	}
+}
+
+func (e *InputError) Unwrap() error {
+	return e.Err
+}
+
+// ChunkWriteError is returned when a chunk file can't be created or written
+// Op - an operation which failed: "create", "write header", "write trailer", "write checksum" or "write"
+// Chunk - an index of the chunk
+type ChunkWriteError struct {
+	Op    string
+	Path  string
+	Chunk int
+	Err   error
+}
+
+func (e *ChunkWriteError) Error() string {
+	switch e.Op {
+	case "create":
+		return fmt.Sprintf("Couldn't create file %s: %v", e.Path, e.Err)
+	case "write header":
+		return fmt.Sprintf("Couldn't write header of chunk file %s : %v", e.Path, e.Err)
+	case "write trailer":
+		return fmt.Sprintf("Couldn't write trailer of chunk file %s : %v", e.Path, e.Err)
+	case "write checksum":
+		return fmt.Sprintf("Couldn't write checksum file %s : %v", e.Path, e.Err)
+	default:
+		return fmt.Sprintf("Couldn't write chunk file %s : %v", e.Path, e.Err)
+	}
+}
+
+func (e *ChunkWriteError) Unwrap() error {
+	return e.Err
+}
+
+// CheckpointError is returned when the checkpoint can't be read or saved
+// Op - an operation which failed: "open", "decode", "create", "write" or "replace"
+// Path - a path of the checkpoint file
+type CheckpointError struct {
+	Op   string
+	Path string
+	Err  error
+}
+
+func (e *CheckpointError) Error() string {
+	switch e.Op {
+	case "decode":
+		return fmt.Sprintf("Couldn't decode checkpoint %s : %v", e.Path, e.Err)
+	case "replace":
+		return fmt.Sprintf("Couldn't replace checkpoint %s : %v", e.Path, e.Err)
+	default:
+		return fmt.Sprintf("Couldn't %s checkpoint %s : %v", e.Op, e.Path, e.Err)
+	}
+}
+
+func (e *CheckpointError) Unwrap() error {
+	return e.Err
+}
+
+// ParseError is returned when the input contains a malformed record
+// Offset - a position of the record in the input
+// Line - a number of the first line of the record starting from 1
+type ParseError struct {
+	Offset int64
+	Line   int
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("Couldn't parse record at line %d (offset %d): %v", e.Line, e.Offset, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError is returned when the batch function fails
+// Batch - an index of the batch starting from 1
+// FirstLine, LastLine - a range of source lines of the batch
+type BatchError struct {
+	Batch     int
+	FirstLine int
+	LastLine  int
+	Err       error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("Couldn't process batch %d (lines %d-%d): %v", e.Batch, e.FirstLine, e.LastLine, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// TransformError is returned when the Transform function fails on a record
+// Offset - a position of the record in the input
+// Line - a number of the first line of the record starting from 1
+type TransformError struct {
+	Offset int64
+	Line   int
+	Err    error
+}
+
+func (e *TransformError) Error() string {
+	return fmt.Sprintf("Couldn't transform record at line %d (offset %d): %v", e.Line, e.Offset, e.Err)
+}
+
+func (e *TransformError) Unwrap() error {
+	return e.Err
+}
+
+// RecordSizeError is returned in the strict mode when a record with a header doesn't fit in a chunk
+type RecordSizeError struct {
+	Record int // number of the data record starting from 1
+	Size   int // size of the record including the header
+	Limit  int
+}
+
+func (e *RecordSizeError) Error() string {
+	return fmt.Sprintf(
+		"record %d of %d bytes including header exceeds file chunk size %d",
+		e.Record,
+		e.Size,
+		e.Limit,
+	)
+}
+
+// HeaderSizeError is returned in the strict mode when the header with the chunk trailer doesn't fit in a chunk
+type HeaderSizeError struct {
+	Size  int // size of the header including the chunk trailer
+	Limit int
+}
+
+func (e *HeaderSizeError) Error() string {
+	return fmt.Sprintf("header of %d bytes including trailer exceeds file chunk size %d", e.Size, e.Limit)
+}
+
+// DecodeError is returned when a field of a record can't be decoded into a struct field
+// Line - a number of the first line of the record starting from 1
+// Column - a name of the column
+type DecodeError struct {
+	Line   int
+	Column string
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("Couldn't decode field %s on line %d: %v", e.Column, e.Line, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// VerifyError is returned when chunks don't reassemble the source
+// Chunk - a path of the first mismatching chunk, it's empty when the chunks end before the source
+// Offset - a position in the source where the data differs
+type VerifyError struct {
+	Chunk  string
+	Offset int64
+}
+
+func (e *VerifyError) Error() string {
+	if e.Chunk == "" {
+		return fmt.Sprintf("Chunks end before the source at offset %d", e.Offset)
+	}
+
+	return fmt.Sprintf("Chunk %s differs from the source at offset %d", e.Chunk, e.Offset)
+}
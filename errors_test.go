@@ -0,0 +1,95 @@
+package split_csv
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read(_ []byte) (int, error) {
+	return 0, r.err
+}
+
+func Test_typedErrors(t *testing.T) {
+	t.Run("It wraps an error of a missing input file", func(t *testing.T) {
+		s := New()
+		s.FileChunkSize = 100
+		_, err := s.Split("testdata/missing.csv", t.TempDir())
+
+		var inputErr *InputError
+		require.True(t, errors.As(err, &inputErr))
+		assert.Equal(t, "stat", inputErr.Op)
+		assert.Equal(t, "testdata/missing.csv", inputErr.Path)
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+	t.Run("It wraps an error of reading the input", func(t *testing.T) {
+		readErr := errors.New("connection reset")
+		s := New()
+		s.FileChunkSize = 100
+		_, err := s.SplitReader(errReader{err: readErr}, t.TempDir(), "test")
+
+		var inputErr *InputError
+		require.True(t, errors.As(err, &inputErr))
+		assert.Equal(t, "read", inputErr.Op)
+		assert.ErrorIs(t, err, readErr)
+		assert.EqualError(t, err, "Couldn't read file bulk: connection reset")
+	})
+	t.Run("It wraps an error of creating a chunk file", func(t *testing.T) {
+		s := New()
+		s.Separator = ";"
+		s.FileChunkSize = 800
+		_, err := s.Split("testdata/test.csv", "testdata/missing")
+
+		var chunkErr *ChunkWriteError
+		require.True(t, errors.As(err, &chunkErr))
+		assert.Equal(t, "create", chunkErr.Op)
+		assert.Equal(t, "testdata/missing/test_1.csv", chunkErr.Path)
+		assert.Equal(t, 1, chunkErr.Chunk)
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+	t.Run("It wraps an error of decoding a checkpoint", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "checkpoint.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"offset":`), 0o644))
+		s := New()
+		s.Separator = ";"
+		s.FileChunkSize = 800
+		s.CheckpointPath = path
+		_, err := s.Resume("testdata/test.csv", t.TempDir())
+
+		var checkpointErr *CheckpointError
+		require.True(t, errors.As(err, &checkpointErr))
+		assert.Equal(t, "decode", checkpointErr.Op)
+		assert.Equal(t, path, checkpointErr.Path)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+	t.Run("It wraps an error of a record which is too large", func(t *testing.T) {
+		s := New()
+		s.Separator = ";"
+		s.FileChunkSize = 100
+		s.StrictChunkSize = true
+		s.AllowSmallInput = true
+		data := "a;b\n1;" + strings.Repeat("x", 100) + "\n"
+		_, err := s.SplitReader(strings.NewReader(data), t.TempDir(), "test")
+
+		var sizeErr *RecordSizeError
+		assert.True(t, errors.As(err, &sizeErr))
+	})
+	t.Run("It formats a parse error", func(t *testing.T) {
+		cause := errors.New("unbalanced quotes")
+		err := error(&ParseError{Offset: 120, Line: 3, Err: cause})
+
+		assert.EqualError(t, err, "Couldn't parse record at line 3 (offset 120): unbalanced quotes")
+		assert.ErrorIs(t, err, cause)
+	})
+}
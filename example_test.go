@@ -6,7 +6,7 @@ import (
 	splitCsv "github.com/tolik505/split-csv"
 )
 
-func ExampleSplitCsv() {
+func ExampleSplitter_Split() {
 	splitter := splitCsv.New()
 	splitter.Separator = ";"     // "," is by default
 	splitter.FileChunkSize = 800 // in bytes
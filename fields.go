@@ -0,0 +1,82 @@
+package split_csv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+)
+
+// parseRecord splits the record on fields
+func parseRecord(record []byte, separator byte) ([]string, error) {
+	reader := csv.NewReader(bytes.NewReader(trimSpacesBeforeQuotes(record, separator)))
+	reader.Comma = rune(separator)
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	fields, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := reader.Read(); err != io.EOF {
+		return nil, ErrUnbalancedQuotes
+	}
+
+	return fields, nil
+}
+
+// trimSpacesBeforeQuotes removes spaces between a separator and an opening quote, so such fields
+// are parsed as quoted ones, spaces of other fields are kept
+func trimSpacesBeforeQuotes(record []byte, separator byte) []byte {
+	if !bytes.Contains(record, []byte(` "`)) {
+		return record
+	}
+	result := make([]byte, 0, len(record))
+	isFieldStart, isQuoted := true, false
+	for i := 0; i < len(record); i++ {
+		c := record[i]
+		if isQuoted {
+			if c == '"' && i+1 < len(record) && record[i+1] == '"' {
+				result = append(result, c)
+				i++
+			} else if c == '"' {
+				isQuoted = false
+			}
+			result = append(result, c)
+			continue
+		}
+		if isFieldStart && c == ' ' && separator != ' ' {
+			end := i
+			for end < len(record) && record[end] == ' ' {
+				end++
+			}
+			if end < len(record) && record[end] == '"' {
+				i, c = end, '"'
+			}
+		}
+		isQuoted = isFieldStart && c == '"'
+		isFieldStart = c == separator || c == '\n'
+		result = append(result, c)
+	}
+
+	return result
+}
+
+// formatInput joins fields in a record of the input format
+func (s Splitter) formatInput(fields []string) []byte {
+	if s.inputFormat() == FormatTSV {
+		return formatTSV(fields)
+	}
+
+	return formatRecord(fields, s.separator())
+}
+
+// formatRecord joins fields in a csv record quoting them if needed
+func formatRecord(fields []string, separator byte) []byte {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Comma = rune(separator)
+	// Writing to bytes.Buffer never fails
+	writer.Write(fields)
+	writer.Flush()
+
+	return buf.Bytes()
+}
@@ -0,0 +1,40 @@
+package split_csv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseRecord(t *testing.T) {
+	tests := []struct {
+		name   string
+		record string
+		want   []string
+	}{
+		{
+			name:   "Spaces of fields are kept",
+			record: "a;  x y ; b\n",
+			want:   []string{"a", "  x y ", " b"},
+		},
+		{
+			name:   "Spaces before an opening quote are skipped",
+			record: "1; \"x; y\";  \"multi\nline\"\n",
+			want:   []string{"1", "x; y", "multi\nline"},
+		},
+		{
+			name:   "Quotes inside of fields are kept",
+			record: "1; a \"b\";\"c \"\" d\"\n",
+			want:   []string{"1", " a \"b\"", "c \" d"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := parseRecord([]byte(tt.record), ';')
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, fields)
+		})
+	}
+}
@@ -9,9 +9,19 @@ type fileOperator interface {
 	Open(name string) (io.ReadCloser, error)
 	Create(name string) (io.WriteCloser, error)
 	Stat(name string) (os.FileInfo, error)
+	Rename(oldpath string, newpath string) error
 	IsNotExist(err error) bool
 }
 
+// chunkOperator returns the operator of chunk files
+func (s Splitter) chunkOperator() fileOperator {
+	if s.chunkOp != nil {
+		return s.chunkOp
+	}
+
+	return s.fileOp
+}
+
 type fileOp struct{}
 
 func (f fileOp) Open(name string) (io.ReadCloser, error) {
@@ -26,6 +36,10 @@ func (f fileOp) Stat(name string) (os.FileInfo, error) {
 	return os.Stat(name)
 }
 
+func (f fileOp) Rename(oldpath string, newpath string) error {
+	return os.Rename(oldpath, newpath)
+}
+
 func (f fileOp) IsNotExist(err error) bool {
 	return os.IsNotExist(err)
 }
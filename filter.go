@@ -0,0 +1,127 @@
+package split_csv
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// FilterFunc decides whether the record should be kept in chunks.
+// Header contains positions of columns by their names, it's nil when the input has no header.
+type FilterFunc func(record []string, header map[string]int) bool
+
+// Operators of declarative filters
+const (
+	FilterEquals  = "equals"
+	FilterMatches = "matches"
+	FilterEmpty   = "empty"
+)
+
+var ErrWrongFilter = errors.New("wrong filter")
+
+// RowFilter is a declarative filter which can be loaded from configuration
+// Column - a header name of the column, Index is used when it's empty
+// Index - an index of the column starting from 0
+// Op - an operator: "equals", "matches" (a regular expression) or "empty"
+// Not - whether the result of the operator is inverted
+type RowFilter struct {
+	Column string `json:"column"`
+	Index  int    `json:"index"`
+	Op     string `json:"op"`
+	Value  string `json:"value"`
+	Not    bool   `json:"not"`
+}
+
+// filter is a RowFilter prepared to be applied to records
+type filter struct {
+	RowFilter
+	regexp *regexp.Regexp
+}
+
+// match checks whether the field matches the filter
+func (f filter) match(field string) bool {
+	var result bool
+	switch f.Op {
+	case FilterEquals:
+		result = field == f.Value
+	case FilterMatches:
+		result = f.regexp.MatchString(field)
+	case FilterEmpty:
+		result = field == ""
+	}
+
+	return result != f.Not
+}
+
+// isFiltering checks whether records should be filtered
+func (s Splitter) isFiltering() bool {
+	return s.Filter != nil || len(s.Filters) > 0
+}
+
+// isParsing checks whether records should be split on fields
+func (s Splitter) isParsing() bool {
+	return s.isTransforming() || s.isFiltering() || len(s.DedupColumns) > 0 || s.recordSink != nil
+}
+
+// validateFilters checks declarative filters
+func (s Splitter) validateFilters() error {
+	_, err := s.prepareFilters()
+
+	return err
+}
+
+// prepareFilters validates declarative filters and compiles their regular expressions
+func (s Splitter) prepareFilters() ([]filter, error) {
+	filters := make([]filter, len(s.Filters))
+	for i, rowFilter := range s.Filters {
+		filters[i] = filter{RowFilter: rowFilter}
+		switch {
+		case rowFilter.Column != "" && !s.hasHeaderNames():
+			return nil, fmt.Errorf("%w: column %s can be used only with header", ErrWrongFilter, rowFilter.Column)
+		case rowFilter.Column == "" && rowFilter.Index < 0:
+			return nil, fmt.Errorf("%w: index %d", ErrWrongFilter, rowFilter.Index)
+		case rowFilter.Op == FilterMatches:
+			re, err := regexp.Compile(rowFilter.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrWrongFilter, err)
+			}
+			filters[i].regexp = re
+		case rowFilter.Op != FilterEquals && rowFilter.Op != FilterEmpty:
+			return nil, fmt.Errorf("%w: unknown operator %s", ErrWrongFilter, rowFilter.Op)
+		}
+	}
+
+	return filters, nil
+}
+
+// filterRecord checks whether the record should be kept applying declarative filters and the filter function
+func (s Splitter) filterRecord(st *state, fields []string) (bool, error) {
+	if st.filters == nil {
+		var err error
+		if st.filters, err = s.prepareFilters(); err != nil {
+			return false, err
+		}
+	}
+	header := st.headerPositions()
+	for _, f := range st.filters {
+		index := f.Index
+		if f.Column != "" {
+			var ok bool
+			if index, ok = header[f.Column]; !ok {
+				return false, fmt.Errorf("%w: %s", ErrUnknownColumn, f.Column)
+			}
+		}
+		field := ""
+		if index < len(fields) {
+			field = fields[index]
+		}
+		if !f.match(field) {
+			return false, nil
+		}
+	}
+	if s.Filter != nil {
+		return s.Filter(fields, header), nil
+	}
+
+	return true, nil
+}
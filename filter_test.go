@@ -0,0 +1,97 @@
+package split_csv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const filterInput = `id;country;email
+1;UA;first@example.com
+2;PL;
+3;UA;third@example.org
+4;DE;fourth@example.com
+`
+
+func Test_SplitFilters_integration(t *testing.T) {
+	tests := []struct {
+		name     string
+		filters  []RowFilter
+		expected string
+		dropped  int
+	}{
+		{
+			name:     "Column equals",
+			filters:  []RowFilter{{Column: "country", Op: FilterEquals, Value: "UA"}},
+			expected: "id;country;email\n1;UA;first@example.com\n3;UA;third@example.org\n",
+			dropped:  2,
+		},
+		{
+			name:     "Column matches a regular expression",
+			filters:  []RowFilter{{Index: 2, Op: FilterMatches, Value: `\.com$`}},
+			expected: "id;country;email\n1;UA;first@example.com\n4;DE;fourth@example.com\n",
+			dropped:  2,
+		},
+		{
+			name: "Column is not empty and not equals",
+			filters: []RowFilter{
+				{Column: "email", Op: FilterEmpty, Not: true},
+				{Column: "country", Op: FilterEquals, Value: "DE", Not: true},
+			},
+			expected: "id;country;email\n1;UA;first@example.com\n3;UA;third@example.org\n",
+			dropped:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSplitter(";", 100)
+			s.Filters = tt.filters
+			result, err := s.SplitReaderWithResult(strings.NewReader(filterInput), t.TempDir(), "test")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, readChunks(t, result.Chunks))
+			assert.Equal(t, tt.dropped, result.DroppedRows)
+		})
+	}
+	t.Run("Filter function", func(t *testing.T) {
+		s := newTestSplitter(";", 100)
+		s.Filter = func(record []string, header map[string]int) bool {
+			return record[header["id"]] > "2"
+		}
+		result, err := s.SplitReaderWithResult(strings.NewReader(filterInput), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			"id;country;email\n3;UA;third@example.org\n4;DE;fourth@example.com\n",
+			readChunks(t, result.Chunks),
+		)
+		assert.Equal(t, 2, result.DroppedRows)
+	})
+	t.Run("Wrong regular expression", func(t *testing.T) {
+		s := newTestSplitter(";", 100)
+		s.Filters = []RowFilter{{Index: 1, Op: FilterMatches, Value: "("}}
+		result, err := s.SplitReader(strings.NewReader(filterInput), t.TempDir(), "test")
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrWrongFilter)
+	})
+	t.Run("Unknown operator", func(t *testing.T) {
+		s := newTestSplitter(";", 100)
+		s.Filters = []RowFilter{{Index: 1, Op: "contains"}}
+		result, err := s.SplitReader(strings.NewReader(filterInput), t.TempDir(), "test")
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "wrong filter: unknown operator contains")
+	})
+	t.Run("Unknown column", func(t *testing.T) {
+		s := newTestSplitter(";", 100)
+		s.Filters = []RowFilter{{Column: "city", Op: FilterEmpty}}
+		result, err := s.SplitReader(strings.NewReader(filterInput), t.TempDir(), "test")
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrUnknownColumn)
+	})
+}
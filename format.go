@@ -0,0 +1,107 @@
+package split_csv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Format defines a format of the input or chunk files
+type Format int
+
+const (
+	// FormatCSV is a csv format, OutputSeparator and QuoteAll can change the style of csv chunks
+	FormatCSV Format = iota + 1
+	// FormatNDJSON is JSON Lines, every line is a record and there is no header.
+	// Every record is written as a JSON object with header names as keys,
+	// or as a JSON array when the input has no header.
+	FormatNDJSON
+	// FormatTSV is tab separated values without quoting, every line is a record.
+	// Fields of the input are read as is. Tabs and line breaks of fields written to TSV chunks
+	// are escaped as \t, \n and \r, because they can't be a part of a field.
+	FormatTSV
+)
+
+// extensions of chunk files by formats
+var extensions = map[Format]string{
+	FormatCSV:    "csv",
+	FormatNDJSON: "ndjson",
+	FormatTSV:    "tsv",
+}
+
+var (
+	ErrWrongFormat        = errors.New("unknown format")
+	ErrFieldsNotSupported = errors.New("records of the input format can't be split on fields")
+	ErrInvalidJSON        = errors.New("invalid JSON")
+)
+
+// inputFormat returns the format of the input, it's csv by default
+func (s Splitter) inputFormat() Format {
+	if s.InputFormat == 0 {
+		return FormatCSV
+	}
+
+	return s.InputFormat
+}
+
+// outputFormat returns the format of chunks, chunks keep the input format by default
+func (s Splitter) outputFormat() Format {
+	if s.OutputFormat == 0 {
+		return s.inputFormat()
+	}
+
+	return s.OutputFormat
+}
+
+// isConverting checks whether records should be written in another format or style
+func (s Splitter) isConverting() bool {
+	return s.outputFormat() != s.inputFormat() || s.OutputSeparator != "" || s.QuoteAll
+}
+
+// hasHeader checks whether the first record of the input is a header
+func (s Splitter) hasHeader() bool {
+	return s.WithHeader && s.inputFormat() != FormatNDJSON
+}
+
+// separator returns the separator of fields in the input
+func (s Splitter) separator() byte {
+	if s.inputFormat() == FormatTSV {
+		return '\t'
+	}
+
+	return s.Separator[0]
+}
+
+// validateFormats checks options of the input and output formats
+func (s Splitter) validateFormats() error {
+	if len([]byte(s.OutputSeparator)) > 1 {
+		return ErrWrongSeparator
+	}
+	if _, ok := extensions[s.inputFormat()]; !ok {
+		return ErrWrongFormat
+	}
+	if _, ok := extensions[s.outputFormat()]; !ok {
+		return ErrWrongFormat
+	}
+	if s.inputFormat() == FormatNDJSON && s.isParsing() {
+		return ErrFieldsNotSupported
+	}
+
+	return nil
+}
+
+// parseFields splits the record of the input format on fields
+func (s Splitter) parseFields(record []byte) ([]string, error) {
+	switch s.inputFormat() {
+	case FormatNDJSON:
+		if !json.Valid(record) {
+			return nil, ErrInvalidJSON
+		}
+		return nil, nil
+	case FormatTSV:
+		return strings.Split(string(bytes.TrimRight(record, "\r\n")), "\t"), nil
+	}
+
+	return parseRecord(record, s.separator())
+}
@@ -0,0 +1,88 @@
+package split_csv
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SplitInputFormat_integration(t *testing.T) {
+	t.Run("It splits NDJSON by lines", func(t *testing.T) {
+		line := `{"id":1,"text":"unbalanced \" quote, and comma"}` + "\n"
+		input := strings.Repeat(line, 10)
+		s := newTestSplitter(",", 100)
+		s.InputFormat = FormatNDJSON
+		result, err := s.SplitReader(strings.NewReader(input), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		assert.Greater(t, len(result), 1)
+		assert.Equal(t, "test_1.ndjson", filepath.Base(result[0]))
+		assert.Equal(t, input, readChunks(t, result))
+	})
+	t.Run("It rejects invalid JSON lines", func(t *testing.T) {
+		input := "{\"id\":1}\n{\"id\":\n{\"id\":3}\n"
+		s := newTestSplitter(",", 100)
+		s.InputFormat = FormatNDJSON
+		s.Malformed = MalformedSkip
+		result, err := s.SplitReaderWithResult(strings.NewReader(input), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		assert.Equal(t, "{\"id\":1}\n{\"id\":3}\n", readChunks(t, result.Chunks))
+		assert.Equal(t, 1, result.RejectedRows)
+	})
+	t.Run("It doesn't split NDJSON records on fields", func(t *testing.T) {
+		s := newTestSplitter(",", 100)
+		s.InputFormat = FormatNDJSON
+		s.ColumnIndexes = []int{0}
+		_, err := s.SplitReader(strings.NewReader("{}\n"), t.TempDir(), "test")
+
+		assert.ErrorIs(t, err, ErrFieldsNotSupported)
+	})
+	t.Run("It splits TSV with header", func(t *testing.T) {
+		input := "id\tname\n" + strings.Repeat("1\t\"quoted\n", 20)
+		s := newTestSplitter(",", 100)
+		s.InputFormat = FormatTSV
+		result, err := s.SplitReader(strings.NewReader(input), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		require.Greater(t, len(result), 1)
+		assert.Equal(t, "test_1.tsv", filepath.Base(result[0]))
+		for _, chunk := range result {
+			assert.True(t, strings.HasPrefix(readChunks(t, []string{chunk}), "id\tname\n"))
+		}
+		content := strings.ReplaceAll(readChunks(t, result), "id\tname\n", "")
+		assert.Equal(t, strings.Repeat("1\t\"quoted\n", 20), content)
+	})
+	t.Run("It converts TSV to csv", func(t *testing.T) {
+		input := "id\tname\n1\tmulti\\nline\n2\t\"quoted\"\\tname\n"
+		s := newTestSplitter(",", 100)
+		s.InputFormat = FormatTSV
+		s.OutputFormat = FormatCSV
+		s.Columns = []string{"name"}
+		result, err := s.SplitReader(strings.NewReader(input), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		assert.Equal(t, "test_1.csv", filepath.Base(result[0]))
+		assert.Equal(t, "name\nmulti\\nline\n\"\"\"quoted\"\"\\tname\"\n", readChunks(t, result))
+	})
+	t.Run("It keeps backslashes of TSV fields", func(t *testing.T) {
+		input := "id\tpath\tcomment\n1\tC:\\dir\\x\tC:\\temp\\new\n2\t\\\\\tend\\\n"
+		s := newTestSplitter(",", 100)
+		s.InputFormat = FormatTSV
+		s.ColumnIndexes = []int{2, 1, 0}
+		result, err := s.SplitReader(strings.NewReader(input), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		assert.Equal(t, "comment\tpath\tid\nC:\\temp\\new\tC:\\dir\\x\t1\nend\\\t\\\\\t2\n", readChunks(t, result))
+
+		s.OutputFormat = FormatCSV
+		s.OutputSeparator = ","
+		result, err = s.SplitReader(strings.NewReader(input), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		assert.Equal(t, "comment,path,id\nC:\\temp\\new,C:\\dir\\x,1\nend\\,\\\\,2\n", readChunks(t, result))
+	})
+}
@@ -0,0 +1,75 @@
+package split_csv
+
+import (
+	"fmt"
+)
+
+// isHeaderSupplied checks whether the header of chunks is given instead of the input header
+func (s Splitter) isHeaderSupplied() bool {
+	return s.Header != nil || s.RawHeader != nil
+}
+
+// hasHeaderNames checks whether columns can be referred by header names
+func (s Splitter) hasHeaderNames() bool {
+	return s.hasHeader() || s.isHeaderSupplied()
+}
+
+// hasChunkHeader checks whether chunks start with a header
+func (s Splitter) hasChunkHeader() bool {
+	return s.hasHeaderNames() && s.outputFormat() != FormatNDJSON
+}
+
+// validateHeader checks options of the supplied header
+func (s Splitter) validateHeader() error {
+	if s.Header != nil && s.RawHeader != nil {
+		return ErrHeaderConflict
+	}
+	if s.isHeaderSupplied() && s.inputFormat() == FormatNDJSON {
+		return ErrFieldsNotSupported
+	}
+
+	return nil
+}
+
+// prepareHeader sets the supplied header of chunks
+func (s Splitter) prepareHeader(st *state) error {
+	record := s.suppliedHeader()
+	fields := s.Header
+	if fields == nil {
+		var err error
+		if fields, err = s.parseFields(record); err != nil {
+			return fmt.Errorf("Couldn't parse the header: %w", err)
+		}
+	}
+
+	return s.setHeader(st, record, fields)
+}
+
+// suppliedHeader returns the supplied header line in the input format
+func (s Splitter) suppliedHeader() []byte {
+	if s.Header != nil {
+		return s.formatInput(s.Header)
+	}
+	if len(s.RawHeader) == 0 || s.RawHeader[len(s.RawHeader)-1] != '\n' {
+		return append(append([]byte{}, s.RawHeader...), '\n')
+	}
+
+	return s.RawHeader
+}
+
+// setHeader stores the header which is written to every chunk, selecting its columns and formatting it if needed
+func (s Splitter) setHeader(st *state, record []byte, fields []string) error {
+	st.headerFields = fields
+	if s.isProjecting() {
+		var err error
+		if fields, err = s.projectRecord(st, fields, true); err != nil {
+			return err
+		}
+	}
+	if s.isTransforming() {
+		record = s.formatOutput(st, fields, true)
+	}
+	st.header = record
+
+	return nil
+}
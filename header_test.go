@@ -0,0 +1,62 @@
+package split_csv
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SplitHeader_integration(t *testing.T) {
+	rows := strings.Repeat("1,\"multi\nline\"\n2,single\n", 5)
+	assertChunks := func(t *testing.T, chunks []string, header string, expected string) {
+		var content string
+		for _, chunk := range chunks {
+			data, err := os.ReadFile(chunk)
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(string(data), header))
+			content += strings.TrimPrefix(string(data), header)
+		}
+		assert.Equal(t, expected, content)
+	}
+
+	t.Run("It adds the header to chunks of an input without header", func(t *testing.T) {
+		s := newTestSplitter(",", 100)
+		s.WithHeader = false
+		s.Header = []string{"id", "comment, text"}
+		result, err := s.SplitReader(strings.NewReader(rows), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		assert.Greater(t, len(result), 1)
+		assertChunks(t, result, "id,\"comment, text\"\n", rows)
+	})
+	t.Run("It replaces the input header", func(t *testing.T) {
+		s := newTestSplitter(",", 100)
+		s.RawHeader = []byte("number,note")
+		result, err := s.SplitReader(strings.NewReader("id,comment\n"+rows), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		assert.Greater(t, len(result), 1)
+		assertChunks(t, result, "number,note\n", rows)
+	})
+	t.Run("It selects columns by names of the supplied header", func(t *testing.T) {
+		s := newTestSplitter(",", 100)
+		s.WithHeader = false
+		s.Header = []string{"id", "comment"}
+		s.Columns = []string{"comment"}
+		result, err := s.SplitReader(strings.NewReader("1,first\n2,second\n"), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		assert.Equal(t, "comment\nfirst\nsecond\n", readChunks(t, result))
+	})
+	t.Run("It fails when the header is given twice", func(t *testing.T) {
+		s := newTestSplitter(",", 100)
+		s.Header = []string{"id"}
+		s.RawHeader = []byte("id")
+		_, err := s.SplitReader(strings.NewReader(rows), t.TempDir(), "test")
+
+		assert.ErrorIs(t, err, ErrHeaderConflict)
+	})
+}
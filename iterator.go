@@ -0,0 +1,186 @@
+package split_csv
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+)
+
+var ErrIteratorClosed = errors.New("chunk iterator is closed")
+
+// ChunkInfo describes a chunk yielded by the iterator
+// Index - an index of the chunk starting from 1
+// Name - a name of the chunk file which SplitReader would create
+type ChunkInfo struct {
+	Index int
+	Name  string
+}
+
+// ChunkIterator yields chunks of the source as streams without creating files
+type ChunkIterator struct {
+	chunks   chan chunkStream
+	done     chan struct{} // closed when the iterator is closed
+	finished chan struct{} // closed when splitting is finished
+	current  *io.PipeReader
+	result   *Result
+	err      error
+}
+
+// chunkStream is a chunk which is being split
+type chunkStream struct {
+	reader *io.PipeReader
+	info   ChunkInfo
+}
+
+// Chunks starts splitting of the source and returns the iterator over its chunks.
+// The source is read only while chunks are read, so memory usage is bounded by the buffer size.
+// Only chunks aren't stored, rejected records and checkpoints are written to files when they're enabled.
+func (s Splitter) Chunks(source io.Reader, outputFilePrefix string) *ChunkIterator {
+	it := &ChunkIterator{
+		chunks:   make(chan chunkStream),
+		done:     make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	op := &pipeOp{it: it, sizes: make(map[string]int64)}
+	s.chunkOp = op
+	go func() {
+		result, err := s.SplitReaderWithResult(source, "", outputFilePrefix)
+		op.close(err)
+		it.result, it.err = result, err
+		close(it.finished)
+	}()
+
+	return it
+}
+
+// Next returns a reader of the next chunk, io.EOF is returned when there are no more chunks.
+// The header is copied to every chunk like in chunk files. The rest of the previous chunk is skipped.
+func (it *ChunkIterator) Next() (io.Reader, ChunkInfo, error) {
+	select {
+	case <-it.done:
+		return nil, ChunkInfo{}, ErrIteratorClosed
+	default:
+	}
+	if it.current != nil {
+		io.Copy(io.Discard, it.current)
+		it.current = nil
+	}
+	select {
+	case chunk := <-it.chunks:
+		it.current = chunk.reader
+		return chunk.reader, chunk.info, nil
+	case <-it.finished:
+		if it.err != nil {
+			return nil, ChunkInfo{}, it.err
+		}
+		return nil, ChunkInfo{}, io.EOF
+	}
+}
+
+// Result returns details of the split, it's nil until all chunks are yielded
+func (it *ChunkIterator) Result() *Result {
+	select {
+	case <-it.finished:
+		return it.result
+	default:
+		return nil
+	}
+}
+
+// Close stops splitting, the source isn't read after the current bulk
+func (it *ChunkIterator) Close() error {
+	select {
+	case <-it.done:
+	default:
+		close(it.done)
+	}
+	if it.current != nil {
+		it.current.CloseWithError(ErrIteratorClosed)
+		it.current = nil
+	}
+
+	return nil
+}
+
+// pipeOp passes chunks to the iterator through pipes instead of files
+type pipeOp struct {
+	it      *ChunkIterator
+	current *io.PipeWriter
+	chunk   int
+	sizes   map[string]int64
+}
+
+func (p *pipeOp) Open(name string) (io.ReadCloser, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// Create completes the current chunk and passes a new one to the iterator
+func (p *pipeOp) Create(name string) (io.WriteCloser, error) {
+	if p.current != nil {
+		p.current.Close()
+	}
+	reader, writer := io.Pipe()
+	p.chunk++
+	select {
+	case p.it.chunks <- chunkStream{reader: reader, info: ChunkInfo{Index: p.chunk, Name: name}}:
+	case <-p.it.done:
+		return nil, ErrIteratorClosed
+	}
+	p.current = writer
+	p.sizes[name] = 0
+
+	return &pipeChunk{PipeWriter: writer, op: p, name: name}, nil
+}
+
+func (p *pipeOp) Stat(name string) (os.FileInfo, error) {
+	size, ok := p.sizes[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return chunkInfo{name: name, size: size}, nil
+}
+
+func (p *pipeOp) Rename(oldpath string, newpath string) error {
+	return &fs.PathError{Op: "rename", Path: oldpath, Err: fs.ErrNotExist}
+}
+
+func (p *pipeOp) IsNotExist(err error) bool {
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// close completes the current chunk, the error is passed to its reader if it's given
+func (p *pipeOp) close(err error) {
+	if p.current != nil {
+		p.current.CloseWithError(err)
+	}
+}
+
+// pipeChunk counts bytes written to the chunk
+type pipeChunk struct {
+	*io.PipeWriter
+	op   *pipeOp
+	name string
+}
+
+func (c *pipeChunk) Write(data []byte) (int, error) {
+	n, err := c.PipeWriter.Write(data)
+	c.op.sizes[c.name] += int64(n)
+
+	return n, err
+}
+
+// chunkInfo describes a chunk which isn't stored in a file
+type chunkInfo struct {
+	name string
+	size int64
+}
+
+func (i chunkInfo) Name() string       { return i.name }
+func (i chunkInfo) Size() int64        { return i.size }
+func (i chunkInfo) Mode() fs.FileMode  { return 0 }
+func (i chunkInfo) ModTime() time.Time { return time.Time{} }
+func (i chunkInfo) IsDir() bool        { return false }
+func (i chunkInfo) Sys() any           { return nil }
@@ -0,0 +1,85 @@
+package split_csv
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Chunks_integration(t *testing.T) {
+	input, err := os.ReadFile("testdata/test.csv")
+	require.NoError(t, err)
+	t.Run("It yields chunks without creating files", func(t *testing.T) {
+		s := newTestSplitter(";", 800)
+		s.bufferSize = 100
+		expected, err := s.SplitReader(bytes.NewReader(input), t.TempDir(), "test")
+		require.NoError(t, err)
+
+		it := s.Chunks(bytes.NewReader(input), "test")
+		for i, path := range expected {
+			reader, info, err := it.Next()
+			require.NoError(t, err)
+			assert.Equal(t, ChunkInfo{Index: i + 1, Name: "test_" + string(rune('1'+i)) + ".csv"}, info)
+			content, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			expectedContent, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, string(expectedContent), string(content))
+			_, err = os.Stat(info.Name)
+			assert.True(t, os.IsNotExist(err))
+		}
+		_, _, err = it.Next()
+		assert.Equal(t, io.EOF, err)
+		assert.Equal(t, []string{"test_1.csv", "test_2.csv", "test_3.csv"}, it.Result().Chunks)
+	})
+	t.Run("It skips the rest of the previous chunk", func(t *testing.T) {
+		s := newTestSplitter(";", 800)
+		s.bufferSize = 100
+		expected, err := s.SplitReader(bytes.NewReader(input), t.TempDir(), "test")
+		require.NoError(t, err)
+		require.Len(t, expected, 3)
+
+		it := s.Chunks(bytes.NewReader(input), "test")
+		var joined bytes.Buffer
+		count := 0
+		for {
+			reader, _, err := it.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			count++
+			if count == 2 {
+				continue
+			}
+			_, err = io.Copy(&joined, reader)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 3, count)
+		assert.Equal(t, readChunks(t, []string{expected[0], expected[2]}), joined.String())
+	})
+	t.Run("It stops splitting on Close", func(t *testing.T) {
+		s := newTestSplitter(";", 800)
+		s.bufferSize = 100
+		it := s.Chunks(bytes.NewReader(input), "test")
+		reader, _, err := it.Next()
+		require.NoError(t, err)
+		require.NoError(t, it.Close())
+
+		_, err = io.ReadAll(reader)
+		assert.ErrorIs(t, err, io.ErrClosedPipe)
+		_, _, err = it.Next()
+		assert.ErrorIs(t, err, ErrIteratorClosed)
+	})
+	t.Run("It returns errors of splitting", func(t *testing.T) {
+		s := newTestSplitter(";;", 800)
+		s.bufferSize = 100
+		_, _, err := s.Chunks(bytes.NewReader(input), "test").Next()
+
+		assert.ErrorIs(t, err, ErrWrongSeparator)
+	})
+}
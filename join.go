@@ -0,0 +1,98 @@
+package split_csv
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrHeadersMismatch = errors.New("headers of chunks don't match")
+
+// Join joins chunks created by the splitter back into one file, the header is written only once
+// and should be the same in all chunks. Chunk trailers are stripped when ChunkTrailer is set.
+func (s Splitter) Join(paths []string, dst io.Writer) error {
+	var header []byte
+	for i, path := range paths {
+		file, err := s.fileOp.Open(path)
+		if err != nil {
+			return &InputError{Op: "open", Path: path, Err: err}
+		}
+		chunkHeader, err := s.joinChunk(file, dst, header, i == 0)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("Couldn't join chunk %s : %w", path, err)
+		}
+		header = chunkHeader
+	}
+
+	return nil
+}
+
+// joinChunk writes data of the chunk to dst and returns its header.
+// The header is written only for the first chunk, otherwise it's compared with the given one.
+func (s Splitter) joinChunk(src io.Reader, dst io.Writer, header []byte, isFirst bool) ([]byte, error) {
+	reader := bufio.NewReaderSize(src, s.bufferSize)
+	var chunkHeader []byte
+	if s.hasChunkHeader() {
+		var err error
+		if chunkHeader, err = s.readHeader(reader); err != nil {
+			return nil, err
+		}
+		if !isFirst && !bytes.Equal(chunkHeader, header) {
+			return nil, ErrHeadersMismatch
+		}
+		if isFirst {
+			if _, err := dst.Write(chunkHeader); err != nil {
+				return nil, fmt.Errorf("Couldn't write joined data: %w", err)
+			}
+		}
+	}
+	if s.ChunkTrailer == "" {
+		if _, err := io.Copy(dst, reader); err != nil {
+			return nil, fmt.Errorf("Couldn't copy chunk data: %w", err)
+		}
+		return chunkHeader, nil
+	}
+	// the last line is the trailer, so every line is written after the next one is read
+	var previous []byte
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, &InputError{Op: "read", Err: err}
+		}
+		if len(line) > 0 && previous != nil {
+			if _, err := dst.Write(previous); err != nil {
+				return nil, fmt.Errorf("Couldn't write joined data: %w", err)
+			}
+		}
+		if len(line) > 0 {
+			previous = line
+		}
+		if err == io.EOF {
+			return chunkHeader, nil
+		}
+	}
+}
+
+// readHeader reads lines of the header from the chunk
+func (s Splitter) readHeader(reader *bufio.Reader) ([]byte, error) {
+	// chunks are read in the output format
+	chunks := s
+	chunks.InputFormat = s.outputFormat()
+	chunks.Separator = string(s.outputSeparator())
+	firstBulk, _ := reader.Peek(reader.Size())
+	st := &state{s: chunks, columnsCount: countCompletedColumns(firstBulk, chunks.separator())}
+	var header []byte
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, &InputError{Op: "read", Err: err}
+		}
+		header = append(header, line...)
+		if err == io.EOF || !isBrokenMultiLine(line, st) {
+			return header, nil
+		}
+	}
+}
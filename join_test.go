@@ -0,0 +1,62 @@
+package split_csv
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Join_integration(t *testing.T) {
+	input := "id,\"multi\nline header\"\n" + strings.Repeat("1,\"multi\nline\"\n2,single\n", 10)
+	t.Run("It joins chunks writing the header once", func(t *testing.T) {
+		s := newTestSplitter(",", 100)
+		chunks, err := s.SplitReader(strings.NewReader(input), t.TempDir(), "test")
+		require.NoError(t, err)
+		require.Greater(t, len(chunks), 1)
+
+		var joined bytes.Buffer
+		require.NoError(t, s.Join(chunks, &joined))
+		assert.Equal(t, input, joined.String())
+	})
+	t.Run("It joins chunks without header", func(t *testing.T) {
+		s := newTestSplitter(",", 100)
+		s.WithHeader = false
+		chunks, err := s.SplitReader(strings.NewReader(input), t.TempDir(), "test")
+		require.NoError(t, err)
+
+		var joined bytes.Buffer
+		require.NoError(t, s.Join(chunks, &joined))
+		assert.Equal(t, input, joined.String())
+	})
+	t.Run("It strips chunk trailers", func(t *testing.T) {
+		s := newTestSplitter(",", 100)
+		s.ChunkTrailer = "TOTAL,%d"
+		chunks, err := s.SplitReader(strings.NewReader(input), t.TempDir(), "test")
+		require.NoError(t, err)
+
+		var joined bytes.Buffer
+		require.NoError(t, s.Join(chunks, &joined))
+		assert.Equal(t, input, joined.String())
+	})
+	t.Run("It fails when headers don't match", func(t *testing.T) {
+		dir := t.TempDir()
+		first := dir + "/first.csv"
+		second := dir + "/second.csv"
+		require.NoError(t, os.WriteFile(first, []byte("id,name\n1,first\n"), 0644))
+		require.NoError(t, os.WriteFile(second, []byte("id,title\n2,second\n"), 0644))
+
+		err := newTestSplitter(",", 100).Join([]string{first, second}, &bytes.Buffer{})
+
+		assert.ErrorIs(t, err, ErrHeadersMismatch)
+		assert.ErrorContains(t, err, second)
+	})
+	t.Run("It fails when a chunk doesn't exist", func(t *testing.T) {
+		err := newTestSplitter(",", 100).Join([]string{t.TempDir() + "/unknown.csv"}, &bytes.Buffer{})
+
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -151,6 +151,44 @@ func (_c *FileOperator_Open_Call) Return(_a0 io.ReadCloser, _a1 error) *FileOper
 	return _c
 }
 
+// Rename provides a mock function with given fields: oldpath, newpath
+func (_m *FileOperator) Rename(oldpath string, newpath string) error {
+	ret := _m.Called(oldpath, newpath)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(oldpath, newpath)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FileOperator_Rename_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Rename'
+type FileOperator_Rename_Call struct {
+	*mock.Call
+}
+
+// Rename is a helper method to define mock.On call
+//   - oldpath string
+//   - newpath string
+func (_e *FileOperator_Expecter) Rename(oldpath interface{}, newpath interface{}) *FileOperator_Rename_Call {
+	return &FileOperator_Rename_Call{Call: _e.mock.On("Rename", oldpath, newpath)}
+}
+
+func (_c *FileOperator_Rename_Call) Run(run func(oldpath string, newpath string)) *FileOperator_Rename_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *FileOperator_Rename_Call) Return(_a0 error) *FileOperator_Rename_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
 // Stat provides a mock function with given fields: name
 func (_m *FileOperator) Stat(name string) (fs.FileInfo, error) {
 	ret := _m.Called(name)
@@ -0,0 +1,98 @@
+package split_csv
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// outputSeparator returns a separator of csv chunks
+func (s Splitter) outputSeparator() byte {
+	if s.OutputSeparator != "" {
+		return s.OutputSeparator[0]
+	}
+
+	return s.separator()
+}
+
+// formatOutput formats fields of the record in the output format, nil is returned when nothing should be written
+func (s Splitter) formatOutput(st *state, fields []string, isHeader bool) []byte {
+	switch s.outputFormat() {
+	case FormatNDJSON:
+		if isHeader {
+			return nil
+		}
+		return formatJSON(fields, st.outputHeader())
+	case FormatTSV:
+		return formatTSV(fields)
+	}
+	if s.QuoteAll {
+		return formatQuoted(fields, s.outputSeparator())
+	}
+
+	return formatRecord(fields, s.outputSeparator())
+}
+
+// formatJSON writes fields as a JSON object with the given keys,
+// positions of fields starting from 1 are used as keys of fields without a name
+func formatJSON(fields []string, keys []string) []byte {
+	if keys == nil {
+		// Marshaling of strings never fails
+		record, _ := json.Marshal(fields)
+		return append(record, '\n')
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range fields {
+		key := strconv.Itoa(i + 1)
+		if i < len(keys) {
+			key = keys[i]
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		v, _ := json.Marshal(field)
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteString("}\n")
+
+	return buf.Bytes()
+}
+
+// tsvReplacer escapes characters which can't be written in fields of TSV, other characters are kept as is,
+// so fields of TSV input are written unchanged
+var tsvReplacer = strings.NewReplacer("\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// formatTSV joins fields with tabs escaping tabs and line breaks inside of them
+func formatTSV(fields []string) []byte {
+	var buf bytes.Buffer
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteByte('\t')
+		}
+		tsvReplacer.WriteString(&buf, field)
+	}
+	buf.WriteByte('\n')
+
+	return buf.Bytes()
+}
+
+// formatQuoted joins fields in a csv record quoting all of them
+func formatQuoted(fields []string, separator byte) []byte {
+	var buf bytes.Buffer
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteByte(separator)
+		}
+		buf.WriteByte('"')
+		buf.WriteString(strings.ReplaceAll(field, `"`, `""`))
+		buf.WriteByte('"')
+	}
+	buf.WriteByte('\n')
+
+	return buf.Bytes()
+}
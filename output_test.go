@@ -0,0 +1,101 @@
+package split_csv
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const outputInput = `id;name;comment
+1;John;"multi
+line"
+2;"Jane ""J""";tab	inside
+`
+
+func Test_SplitOutputFormat_integration(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(s *Splitter)
+		extension string
+		expected  string
+	}{
+		{
+			name: "NDJSON with header names as keys",
+			configure: func(s *Splitter) {
+				s.OutputFormat = FormatNDJSON
+			},
+			extension: ".ndjson",
+			expected: `{"id":"1","name":"John","comment":"multi\nline"}` + "\n" +
+				`{"id":"2","name":"Jane \"J\"","comment":"tab\tinside"}` + "\n",
+		},
+		{
+			name: "NDJSON with selected columns",
+			configure: func(s *Splitter) {
+				s.OutputFormat = FormatNDJSON
+				s.Columns = []string{"name", "id"}
+			},
+			extension: ".ndjson",
+			expected:  `{"name":"John","id":"1"}` + "\n" + `{"name":"Jane \"J\"","id":"2"}` + "\n",
+		},
+		{
+			name: "NDJSON without header",
+			configure: func(s *Splitter) {
+				s.OutputFormat = FormatNDJSON
+				s.WithHeader = false
+			},
+			extension: ".ndjson",
+			expected: `["id","name","comment"]` + "\n" + `["1","John","multi\nline"]` + "\n" +
+				`["2","Jane \"J\"","tab\tinside"]` + "\n",
+		},
+		{
+			name: "TSV",
+			configure: func(s *Splitter) {
+				s.OutputFormat = FormatTSV
+			},
+			extension: ".tsv",
+			expected:  "id\tname\tcomment\n1\tJohn\tmulti\\nline\n2\tJane \"J\"\ttab\\tinside\n",
+		},
+		{
+			name: "CSV with another separator and quoted fields",
+			configure: func(s *Splitter) {
+				s.OutputSeparator = "|"
+				s.QuoteAll = true
+			},
+			extension: ".csv",
+			expected: "\"id\"|\"name\"|\"comment\"\n\"1\"|\"John\"|\"multi\nline\"\n" +
+				"\"2\"|\"Jane \"\"J\"\"\"|\"tab\tinside\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSplitter(";", 100)
+			tt.configure(&s)
+			result, err := s.SplitReader(strings.NewReader(outputInput), t.TempDir(), "test")
+
+			require.NoError(t, err)
+			require.Len(t, result, 1)
+			assert.Equal(t, "test_1"+tt.extension, filepath.Base(result[0]))
+			assert.Equal(t, tt.expected, readChunks(t, result))
+		})
+	}
+	t.Run("It rolls NDJSON chunks over", func(t *testing.T) {
+		s := newTestSplitter(";", 100)
+		s.OutputFormat = FormatNDJSON
+		input := "id;value\n" + strings.Repeat("1;some value\n", 20)
+		result, err := s.SplitReader(strings.NewReader(input), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		assert.Greater(t, len(result), 1)
+		assert.Equal(t, strings.Repeat(`{"id":"1","value":"some value"}`+"\n", 20), readChunks(t, result))
+	})
+	t.Run("It fails on unknown output format", func(t *testing.T) {
+		s := newTestSplitter(";", 100)
+		s.OutputFormat = Format(10)
+		_, err := s.SplitReader(strings.NewReader(outputInput), t.TempDir(), "test")
+
+		assert.ErrorIs(t, err, ErrWrongFormat)
+	})
+}
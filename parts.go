@@ -0,0 +1,81 @@
+package split_csv
+
+import (
+	"bufio"
+	"io"
+)
+
+// partsPlan describes how records are distributed between a fixed number of chunks
+type partsPlan struct {
+	parts    int
+	records  int
+	dataSize int64
+}
+
+// isChunkCompleted checks whether the chunk should be completed after the given number of records.
+// Chunks are completed on equal shares of the data size, but earlier if the rest of the records
+// is just enough to fill the rest of the chunks.
+func (p partsPlan) isChunkCompleted(chunk int, records int, dataSize int64) bool {
+	if chunk >= p.parts || records >= p.records {
+		return false
+	}
+
+	return dataSize*int64(p.parts) >= int64(chunk)*p.dataSize || p.records-records <= p.parts-chunk
+}
+
+// isDroppingRecords checks whether records of the input can be dropped, so they can't be counted in advance
+func (s Splitter) isDroppingRecords() bool {
+	return s.isFiltering() || s.Transform != nil || s.isDeduplicating() ||
+		s.Malformed == MalformedSkip || s.Malformed == MalformedReject || s.TrailerPattern != ""
+}
+
+// validateParts checks that records are distributed between parts as they're counted by planParts
+func (s Splitter) validateParts() error {
+	if s.Parts > 0 && s.isDroppingRecords() {
+		return ErrPartsDropRecords
+	}
+
+	return nil
+}
+
+// planParts scans the whole source to count records and rewinds it to the beginning
+func (s Splitter) planParts(source io.Reader) (*partsPlan, error) {
+	seeker, ok := source.(io.Seeker)
+	if !ok {
+		return nil, ErrNotSeekable
+	}
+	reader := bufio.NewReaderSize(source, s.bufferSize)
+	firstBulk, _ := reader.Peek(s.bufferSize)
+	st := &state{
+		s:            s,
+		isFirstLine:  true,
+		columnsCount: countCompletedColumns(firstBulk, s.separator()),
+	}
+	plan := &partsPlan{parts: s.Parts}
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, &InputError{Op: "read", Err: err}
+		}
+		if len(line) > 0 {
+			if st.isFirstLine && s.hasHeader() {
+				st.isFirstLine = isBrokenMultiLine(line, st)
+			} else {
+				plan.dataSize += int64(len(line))
+				if err == io.EOF || !isBrokenMultiLine(line, st) {
+					plan.records++
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	// trailer records aren't written to chunks
+	plan.records = max(plan.records-s.TrailerLines, 0)
+	if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+		return nil, &InputError{Op: "seek", Err: err}
+	}
+
+	return plan, nil
+}
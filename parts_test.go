@@ -0,0 +1,99 @@
+package split_csv
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SplitParts_integration(t *testing.T) {
+	input := "testdata/test.csv"
+	source, _ := os.ReadFile(input)
+	header := source[:strings.IndexByte(string(source), '\n')+1]
+	for _, parts := range []int{2, 3, 4, 7, 40} {
+		t.Run(fmt.Sprintf("It splits the file on %d equal parts", parts), func(t *testing.T) {
+			s := New()
+			s.Separator = ";"
+			s.Parts = parts
+			s.bufferSize = 100
+			result, err := s.Split(input, t.TempDir())
+			require.NoError(t, err)
+			require.Len(t, result, parts)
+			minSize, maxSize := int64(len(source)), int64(0)
+			for _, chunk := range result {
+				stat, err := os.Stat(chunk)
+				require.NoError(t, err)
+				require.Greater(t, stat.Size(), int64(len(header)))
+				minSize = min(minSize, stat.Size())
+				maxSize = max(maxSize, stat.Size())
+			}
+			// boundaries are shifted from equal shares not more than by one record
+			assert.LessOrEqual(t, maxSize-minSize, 2*int64(len(header)))
+			assert.Equal(t, string(source), string(joinChunks(t, result, header)))
+		})
+	}
+	t.Run("It splits the file with multiline cells on parts", func(t *testing.T) {
+		s := New()
+		s.Separator = ";"
+		s.Parts = 7
+		s.bufferSize = 100
+		result, err := s.Split("testdata/test_multiline_cells.csv", t.TempDir())
+		require.NoError(t, err)
+		assert.Len(t, result, 7)
+	})
+	t.Run("It creates a chunk for every record when records are uneven", func(t *testing.T) {
+		dir := t.TempDir()
+		input := filepath.Join(dir, "uneven.csv")
+		content := "a;b\n1;2\n3;4\n5;" + strings.Repeat("x", 1000) + "\n"
+		require.NoError(t, os.WriteFile(input, []byte(content), 0644))
+		s := New()
+		s.Separator = ";"
+		s.Parts = 3
+		s.bufferSize = 100
+		result, err := s.Split(input, dir)
+		require.NoError(t, err)
+		require.Len(t, result, 3)
+		for i, expected := range []string{"a;b\n1;2\n", "a;b\n3;4\n", "a;b\n5;"} {
+			actual, _ := os.ReadFile(result[i])
+			assert.True(t, strings.HasPrefix(string(actual), expected))
+		}
+	})
+	t.Run("It fails to split a reader on parts", func(t *testing.T) {
+		s := New()
+		s.Parts = 3
+		result, err := s.SplitReader(strings.NewReader("a,b\n"), t.TempDir(), "test")
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrPartsNotSupported)
+	})
+	t.Run("It fails to split on parts when records are dropped", func(t *testing.T) {
+		for name, option := range map[string]func(s *Splitter){
+			"filters":        func(s *Splitter) { s.Filters = []RowFilter{{Index: 0, Op: FilterEquals, Value: "1"}} },
+			"transform":      func(s *Splitter) { s.Transform = func(record []string) ([]string, error) { return nil, nil } },
+			"dedup":          func(s *Splitter) { s.Dedup = true },
+			"malformed":      func(s *Splitter) { s.Malformed = MalformedSkip },
+			"trailerPattern": func(s *Splitter) { s.TrailerPattern = "^TOTAL" },
+		} {
+			s := New()
+			s.Separator = ";"
+			s.Parts = 4
+			option(&s)
+			result, err := s.Split(input, t.TempDir())
+			assert.Nil(t, result, name)
+			assert.ErrorIs(t, err, ErrPartsDropRecords, name)
+		}
+	})
+}
+
+func Test_partsPlan_isChunkCompleted(t *testing.T) {
+	plan := partsPlan{parts: 3, records: 10, dataSize: 300}
+	assert.False(t, plan.isChunkCompleted(1, 3, 90))
+	assert.True(t, plan.isChunkCompleted(1, 4, 100))
+	assert.True(t, plan.isChunkCompleted(2, 9, 150))
+	assert.False(t, plan.isChunkCompleted(3, 9, 290))
+	assert.False(t, plan.isChunkCompleted(2, 10, 300))
+}
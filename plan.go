@@ -0,0 +1,126 @@
+package split_csv
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+var ErrChunkOutOfRange = errors.New("chunk index is out of range")
+
+// ChunkRange is a byte range of the source holding records of a chunk
+// Offset - a position of the first record of the chunk in the source
+// Size - a size of the records of the chunk in bytes
+// Records - a number of the records in the chunk
+type ChunkRange struct {
+	Offset  int64
+	Size    int64
+	Records int
+}
+
+// ChunkPlan describes chunks of the source which can be read without creating files
+// Header - the header which is prepended to every chunk, it's nil when the input has no header
+// Chunks - byte ranges of the chunks in the source
+type ChunkPlan struct {
+	Header []byte
+	Chunks []ChunkRange
+	source io.ReaderAt
+}
+
+// Plan finds boundaries of chunks in the source of the given size without reading it into memory.
+// Chunks are aligned to record starts. A chunk is completed when its size including the header reaches
+// FileChunkSize, in the strict mode it's completed before the record which would exceed FileChunkSize.
+// With Parts records are counted first and distributed between parts like Split does.
+// Options changing records such as filters aren't applied.
+func (s Splitter) Plan(source io.ReaderAt, size int64) (*ChunkPlan, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	var parts *partsPlan
+	if s.Parts > 0 {
+		var err error
+		if parts, err = s.planParts(io.NewSectionReader(source, 0, size)); err != nil {
+			return nil, err
+		}
+	} else if _, err := s.checkInputSize(size); err != nil {
+		return nil, err
+	}
+
+	plan := &ChunkPlan{source: source}
+	if s.isHeaderSupplied() {
+		plan.Header = s.suppliedHeader()
+	}
+	scanner := s.NewRecordScanner(io.NewSectionReader(source, 0, size))
+	var current ChunkRange
+	var records int
+	var dataSize int64
+	for scanner.Scan() {
+		record := scanner.Bytes()
+		if scanner.Offset() == 0 && s.hasHeader() {
+			if !s.isHeaderSupplied() {
+				plan.Header = bytes.Clone(record)
+				if !bytes.HasSuffix(plan.Header, []byte{'\n'}) {
+					plan.Header = append(plan.Header, '\n')
+				}
+			}
+			current.Offset = int64(len(record))
+			continue
+		}
+		records++
+		recordSize := int64(len(record))
+		dataSize += recordSize
+		if parts != nil {
+			current.Size += recordSize
+			current.Records++
+			if parts.isChunkCompleted(len(plan.Chunks)+1, records, dataSize) {
+				plan.Chunks = append(plan.Chunks, current)
+				current = ChunkRange{Offset: current.Offset + current.Size}
+			}
+			continue
+		}
+		chunkSize := int64(len(plan.Header)) + current.Size
+		if s.StrictChunkSize {
+			if limit := int64(len(plan.Header)) + recordSize; limit > int64(s.FileChunkSize) {
+				return nil, &RecordSizeError{Record: records, Size: int(limit), Limit: s.FileChunkSize}
+			}
+			if current.Records > 0 && chunkSize+recordSize > int64(s.FileChunkSize) {
+				plan.Chunks = append(plan.Chunks, current)
+				current = ChunkRange{Offset: current.Offset + current.Size}
+			}
+		}
+		current.Size += recordSize
+		current.Records++
+		if !s.StrictChunkSize && int64(len(plan.Header))+current.Size >= int64(s.FileChunkSize) {
+			plan.Chunks = append(plan.Chunks, current)
+			current = ChunkRange{Offset: current.Offset + current.Size}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if current.Records > 0 || len(plan.Chunks) == 0 {
+		plan.Chunks = append(plan.Chunks, current)
+	}
+
+	return plan, nil
+}
+
+// Len returns a number of the chunks
+func (p *ChunkPlan) Len() int {
+	return len(p.Chunks)
+}
+
+// Chunk returns a reader of the chunk with the given index starting from 0,
+// the header is followed by records of the chunk read directly from the source
+func (p *ChunkPlan) Chunk(i int) (io.Reader, error) {
+	if i < 0 || i >= len(p.Chunks) {
+		return nil, ErrChunkOutOfRange
+	}
+	chunk := p.Chunks[i]
+	records := io.NewSectionReader(p.source, chunk.Offset, chunk.Size)
+	if p.Header == nil {
+		return records, nil
+	}
+
+	return io.MultiReader(bytes.NewReader(p.Header), records), nil
+}
@@ -0,0 +1,131 @@
+package split_csv
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Plan(t *testing.T) {
+	readChunks := func(t *testing.T, plan *ChunkPlan) []string {
+		var chunks []string
+		for i := 0; i < plan.Len(); i++ {
+			reader, err := plan.Chunk(i)
+			require.NoError(t, err)
+			data, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			chunks = append(chunks, string(data))
+		}
+		return chunks
+	}
+
+	t.Run("It plans chunks which are equal to split files", func(t *testing.T) {
+		input, err := os.ReadFile("testdata/test.csv")
+		require.NoError(t, err)
+		for _, strict := range []bool{false, true} {
+			s := New()
+			s.Separator = ";"
+			s.FileChunkSize = 400
+			s.StrictChunkSize = strict
+			dir := t.TempDir()
+			files, err := s.Split("testdata/test.csv", dir)
+			require.NoError(t, err)
+			var expected []string
+			for _, file := range files {
+				data, err := os.ReadFile(filepath.Join(file))
+				require.NoError(t, err)
+				expected = append(expected, string(data))
+			}
+
+			plan, err := s.Plan(bytes.NewReader(input), int64(len(input)))
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, readChunks(t, plan))
+			assert.Equal(t, "Test header 1; Test header 2; Test header 3; Test header 4; Test header 5\n", string(plan.Header))
+		}
+	})
+	t.Run("It plans chunks of parts which are equal to split files", func(t *testing.T) {
+		for _, path := range []string{"testdata/test.csv", "testdata/test_multiline_cells.csv"} {
+			input, err := os.ReadFile(path)
+			require.NoError(t, err)
+			for _, parts := range []int{3, 4, 7} {
+				s := New()
+				s.Separator = ";"
+				s.Parts = parts
+				s.bufferSize = 100
+				files, err := s.Split(path, t.TempDir())
+				require.NoError(t, err)
+				require.Len(t, files, parts)
+				var expected []string
+				for _, file := range files {
+					data, err := os.ReadFile(file)
+					require.NoError(t, err)
+					expected = append(expected, string(data))
+				}
+
+				plan, err := s.Plan(bytes.NewReader(input), int64(len(input)))
+				require.NoError(t, err)
+
+				assert.Equal(t, expected, readChunks(t, plan))
+			}
+		}
+	})
+	t.Run("It aligns chunks to records", func(t *testing.T) {
+		filler := strings.Repeat("x", 40)
+		records := []string{
+			"1,\"multi\nline " + filler + "\"\n",
+			"2," + filler + "\n",
+			"3,\"last\nrecord " + filler + "\"\n",
+		}
+		input := "id,comment\n" + strings.Join(records, "")
+		s := New()
+		s.FileChunkSize = 100
+		s.AllowSmallInput = true
+
+		plan, err := s.Plan(strings.NewReader(input), int64(len(input)))
+		require.NoError(t, err)
+
+		assert.Equal(t, []ChunkRange{
+			{Offset: 11, Size: 99, Records: 2},
+			{Offset: 110, Size: 57, Records: 1},
+		}, plan.Chunks)
+		assert.Equal(t, []string{
+			"id,comment\n" + records[0] + records[1],
+			"id,comment\n" + records[2],
+		}, readChunks(t, plan))
+	})
+	t.Run("It plans chunks without header", func(t *testing.T) {
+		record := strings.Repeat("a", 59) + "\n"
+		s := New()
+		s.WithHeader = false
+		s.FileChunkSize = 100
+		s.StrictChunkSize = true
+
+		input := strings.Repeat(record, 3)
+		plan, err := s.Plan(strings.NewReader(input), int64(len(input)))
+		require.NoError(t, err)
+
+		assert.Nil(t, plan.Header)
+		assert.Equal(t, []string{record, record, record}, readChunks(t, plan))
+	})
+	t.Run("It returns errors", func(t *testing.T) {
+		input := "id\n1\n"
+		s := New()
+		s.FileChunkSize = 100
+
+		_, err := s.Plan(strings.NewReader(input), int64(len(input)))
+		assert.ErrorIs(t, err, ErrBigFileChunkSize)
+
+		s.AllowSmallInput = true
+		plan, err := s.Plan(strings.NewReader(input), int64(len(input)))
+		require.NoError(t, err)
+		_, err = plan.Chunk(1)
+		assert.ErrorIs(t, err, ErrChunkOutOfRange)
+	})
+}
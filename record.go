@@ -0,0 +1,236 @@
+package split_csv
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// isRecordMode checks whether data should be handled record by record instead of bulks of lines
+func (s Splitter) isRecordMode() bool {
+	return s.StrictChunkSize || s.isValidating() || s.isParsing() || s.isDeduplicating() ||
+		s.isStrippingTrailer() || s.ChunkTrailer != ""
+}
+
+// readRecordsFromBulk reads bulk line by line and collects lines in records
+func (s Splitter) readRecordsFromBulk(st *state) error {
+	for {
+		bytesLine, err := st.fileBuffer.ReadBytes('\n')
+		if len(st.brokenLine) > 0 {
+			bytesLine = append(st.brokenLine, bytesLine...)
+			st.brokenLine = []byte{}
+		}
+		if err == io.EOF {
+			st.brokenLine = bytesLine
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Couldn't read bytes from buffer: %w", err)
+		}
+		if err := s.readRecordLine(st, bytesLine); err != nil {
+			return err
+		}
+	}
+}
+
+// readRecordLine appends the line to the current record and writes the record when it's completed
+func (s Splitter) readRecordLine(st *state, line []byte) error {
+	if len(st.record) == 0 {
+		st.recordLine = st.line + 1
+		st.recordQuotes = 0
+	}
+	st.record = append(st.record, line...)
+	st.line++
+	st.lineOffset += int64(len(line))
+	if !s.isRecordCompleted(st, line) {
+		if s.isValidating() && len(st.record) > s.maxRecordSize() {
+			return s.recoverRecord(st, ErrRecordTooLong)
+		}
+		return nil
+	}
+	record := st.record
+	st.record = nil
+	var fields []string
+	if s.isValidating() {
+		var err error
+		if fields, err = s.validateRecord(st, record); err != nil {
+			// Quotes of a malformed multiline record are likely to be paired wrong,
+			// so the first line is treated as an unterminated one
+			if isMultiLine(record) && !errors.Is(err, ErrRecordTooLong) {
+				st.record = record
+				return s.recoverRecord(st, ErrUnbalancedQuotes)
+			}
+			offset := st.lineOffset - int64(len(record))
+			return s.handleMalformed(st, record, st.recordLine, offset, err)
+		}
+	}
+
+	return s.handleRecord(st, record, fields)
+}
+
+// handleRecord transforms the completed record if needed and writes it as a header or a data record.
+// Fields are parsed from the record when they aren't given.
+func (s Splitter) handleRecord(st *state, record []byte, fields []string) error {
+	isHeader := st.isFirstLine && s.hasHeader()
+	st.recordSize = int64(len(record))
+	if s.isParsing() && fields == nil {
+		var err error
+		if fields, err = s.parseFields(record); err != nil {
+			offset := st.lineOffset - int64(len(record))
+			return &ParseError{Offset: offset, Line: st.recordLine, Err: err}
+		}
+	}
+	if isHeader {
+		st.isFirstLine = false
+		if s.isHeaderSupplied() {
+			return nil
+		}
+		return s.setHeader(st, record, fields)
+	}
+	if s.isStrippingTrailer() {
+		return s.holdRecord(st, record, fields)
+	}
+
+	return s.processRecord(st, record, fields)
+}
+
+// processRecord filters and transforms the data record and writes it
+func (s Splitter) processRecord(st *state, record []byte, fields []string) error {
+	lastLine := st.recordLine + bytes.Count(bytes.TrimRight(record, "\r\n"), []byte{'\n'})
+	if s.isFiltering() {
+		keep, err := s.filterRecord(st, fields)
+		if err != nil {
+			return err
+		}
+		if !keep {
+			st.dropped++
+			return nil
+		}
+	}
+	if s.Transform != nil {
+		var err error
+		if fields, err = s.Transform(fields); err != nil {
+			offset := st.lineOffset - int64(len(record))
+			return &TransformError{Offset: offset, Line: st.recordLine, Err: err}
+		}
+		if fields == nil {
+			st.dropped++
+			return nil
+		}
+	}
+	var key []byte
+	if s.isDeduplicating() && len(s.DedupColumns) > 0 {
+		var err error
+		if key, err = s.dedupKey(st, fields); err != nil {
+			return err
+		}
+	}
+	if s.isProjecting() {
+		var err error
+		if fields, err = s.projectRecord(st, fields, false); err != nil {
+			return err
+		}
+	}
+	if s.isTransforming() {
+		record = s.formatOutput(st, fields, false)
+	}
+	if s.isDeduplicating() {
+		if key == nil {
+			key = bytes.TrimRight(record, "\r\n")
+		}
+		if duplicate, err := s.isDuplicate(st, key); err != nil || duplicate {
+			return err
+		}
+	}
+	if s.recordSink != nil {
+		return s.recordSink(st, fields, lastLine)
+	}
+
+	return s.writeRecord(st, record)
+}
+
+// isRecordCompleted checks whether the line is the last line of the record.
+// Quotes of csv should be balanced in the validation mode, otherwise the number of columns is checked.
+func (s Splitter) isRecordCompleted(st *state, line []byte) bool {
+	if s.isValidating() && s.inputFormat() == FormatCSV {
+		st.recordQuotes += bytes.Count(line, []byte{'"'})
+
+		return st.recordQuotes%2 == 0
+	}
+
+	return !isBrokenMultiLine(line, st)
+}
+
+// finishRecords handles the last bulk and writes the rest of data, even an incomplete record
+func (s Splitter) finishRecords(st *state, lastBulk []byte) error {
+	if len(lastBulk) > 0 {
+		st.fileBuffer = bytes.NewBuffer(lastBulk)
+		if err := s.readRecordsFromBulk(st); err != nil {
+			return err
+		}
+	}
+	if len(st.brokenLine) > 0 {
+		line := st.brokenLine
+		st.brokenLine = nil
+		if err := s.readRecordLine(st, line); err != nil {
+			return err
+		}
+	}
+	for len(st.record) > 0 && s.isValidating() {
+		if err := s.recoverRecord(st, ErrUnbalancedQuotes); err != nil {
+			return err
+		}
+	}
+	if len(st.record) > 0 {
+		record := st.record
+		st.record = nil
+		if err := s.handleRecord(st, record, nil); err != nil {
+			return err
+		}
+	}
+	if st.bulkBuffer.Len() == 0 && (len(st.result) > 0 || s.recordSink != nil) {
+		return nil
+	}
+
+	return s.saveBulkToFile(st)
+}
+
+// writeRecord puts the record into the bulk buffer, completing the current chunk before it if needed
+func (s Splitter) writeRecord(st *state, record []byte) error {
+	if s.ChunkTrailer != "" && !bytes.HasSuffix(record, []byte{'\n'}) {
+		// the trailer should start on a new line
+		record = append(record, '\n')
+	}
+	if s.StrictChunkSize {
+		if size := len(st.header) + len(record) + s.trailerSize(1); size > s.FileChunkSize {
+			return &RecordSizeError{
+				Record: st.records + 1,
+				Size:   size,
+				Limit:  s.FileChunkSize,
+			}
+		}
+		chunkSize := st.chunkSize
+		if chunkSize == 0 {
+			chunkSize = int64(len(st.header))
+		}
+		size := chunkSize + int64(st.bulkBuffer.Len()+len(record)+s.trailerSize(st.chunkRecords+1))
+		if size > int64(s.FileChunkSize) {
+			st.rollover = true
+			st.pending = record
+			if err := s.saveBulkToFile(st); err != nil {
+				return err
+			}
+			st.pending = nil
+		}
+	}
+	if _, err := st.bulkBuffer.Write(record); err != nil {
+		return fmt.Errorf("Couldn't write to the bulk buffer: %w", err)
+	}
+	st.completeRecord()
+	if st.rollover || st.isBulkBufferBiggerOrEqualsFileChunkSize() {
+		return s.saveBulkToFile(st)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package split_csv
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SplitStrictChunkSize_integration(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		headerLines   int
+		fileChunkSize int
+		bufferSize    int
+	}{
+		{
+			name:          "Default flow",
+			input:         "testdata/test.csv",
+			headerLines:   1,
+			fileChunkSize: 300,
+			bufferSize:    1000,
+		},
+		{
+			name:          "With small buffer",
+			input:         "testdata/test.csv",
+			fileChunkSize: 300,
+			headerLines:   1,
+			bufferSize:    100,
+		},
+		{
+			name:          "Multiline cells",
+			input:         "testdata/test_multiline_cells.csv",
+			headerLines:   3,
+			fileChunkSize: 400,
+			bufferSize:    90,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New()
+			s.Separator = ";"
+			s.FileChunkSize = tt.fileChunkSize
+			s.StrictChunkSize = true
+			s.bufferSize = tt.bufferSize
+			result, err := s.Split(tt.input, t.TempDir())
+			require.NoError(t, err)
+			require.Greater(t, len(result), 1)
+			for _, chunk := range result {
+				stat, err := os.Stat(chunk)
+				require.NoError(t, err)
+				assert.LessOrEqual(t, stat.Size(), int64(tt.fileChunkSize), chunk)
+			}
+			source, _ := os.ReadFile(tt.input)
+			header := bytes.SplitAfterN(source, []byte("\n"), tt.headerLines+1)
+			header = header[:tt.headerLines]
+			assert.Equal(t, string(source), string(joinChunks(t, result, bytes.Join(header, nil))))
+		})
+	}
+	t.Run("It fails when a record doesn't fit in a chunk", func(t *testing.T) {
+		s := New()
+		s.Separator = ";"
+		s.FileChunkSize = 120
+		s.StrictChunkSize = true
+		result, err := s.Split("testdata/test.csv", t.TempDir())
+		assert.Nil(t, result)
+		var sizeErr *RecordSizeError
+		require.True(t, errors.As(err, &sizeErr))
+		assert.Equal(t, &RecordSizeError{Record: 1, Size: 124, Limit: 120}, sizeErr)
+		assert.EqualError(t, err, "record 1 of 124 bytes including header exceeds file chunk size 120")
+	})
+	t.Run("It fails when a header with a trailer doesn't fit in a chunk", func(t *testing.T) {
+		s := newTestSplitter(";", 100)
+		s.StrictChunkSize = true
+		s.ChunkTrailer = "TOTAL;%d"
+		input := "id;" + strings.Repeat("x", 90) + "\n"
+		result, err := s.SplitReader(strings.NewReader(input), t.TempDir(), "test")
+		assert.Nil(t, result)
+		var sizeErr *HeaderSizeError
+		require.True(t, errors.As(err, &sizeErr))
+		assert.Equal(t, &HeaderSizeError{Size: 102, Limit: 100}, sizeErr)
+	})
+}
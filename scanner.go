@@ -0,0 +1,90 @@
+package split_csv
+
+import (
+	"bufio"
+	"io"
+)
+
+// RecordScanner reads raw records of the input one by one like bufio.Scanner does with lines.
+// Records including multiline ones are detected in the same way as by the splitter,
+// the header is returned as the first record.
+type RecordScanner struct {
+	s       Splitter
+	reader  *bufio.Reader
+	st      *state
+	record  []byte
+	offset  int64
+	line    int
+	next    int64 // offset of the next record
+	lines   int   // number of read lines
+	err     error
+	started bool
+}
+
+// NewRecordScanner initializes the scanner of records of the source using the separator and the input format
+func (s Splitter) NewRecordScanner(source io.Reader) *RecordScanner {
+	return &RecordScanner{
+		s:      s,
+		reader: bufio.NewReaderSize(source, s.bufferSize),
+		st:     &state{s: s},
+	}
+}
+
+// Scan reads the next record, false is returned at the end of the input or on an error
+func (r *RecordScanner) Scan() bool {
+	if r.err != nil {
+		return false
+	}
+	if !r.started {
+		r.started = true
+		firstBulk, _ := r.reader.Peek(r.reader.Size())
+		r.st.columnsCount = countCompletedColumns(firstBulk, r.s.separator())
+	}
+	r.record = r.record[:0]
+	r.offset = r.next
+	r.line = r.lines + 1
+	r.st.recordQuotes = 0
+	for {
+		line, err := r.reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			r.err = &InputError{Op: "read", Offset: r.next, Err: err}
+			return false
+		}
+		if len(line) > 0 {
+			r.record = append(r.record, line...)
+			r.next += int64(len(line))
+			r.lines++
+		}
+		if err == io.EOF {
+			r.err = io.EOF
+			return len(r.record) > 0
+		}
+		if r.s.isRecordCompleted(r.st, line) {
+			return true
+		}
+	}
+}
+
+// Bytes returns the raw record including its line break, the data is valid until the next Scan
+func (r *RecordScanner) Bytes() []byte {
+	return r.record
+}
+
+// Offset returns a position of the record in the source
+func (r *RecordScanner) Offset() int64 {
+	return r.offset
+}
+
+// Line returns a number of the first line of the record starting from 1
+func (r *RecordScanner) Line() int {
+	return r.line
+}
+
+// Err returns the first error which has occurred during reading, nil is returned at the end of the input
+func (r *RecordScanner) Err() error {
+	if r.err == io.EOF {
+		return nil
+	}
+
+	return r.err
+}
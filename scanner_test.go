@@ -0,0 +1,61 @@
+package split_csv
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RecordScanner(t *testing.T) {
+	type record struct {
+		data   string
+		offset int64
+		line   int
+	}
+	scanAll := func(t *testing.T, s Splitter, input string) []record {
+		scanner := s.NewRecordScanner(iotest.OneByteReader(strings.NewReader(input)))
+		var records []record
+		for scanner.Scan() {
+			records = append(records, record{string(scanner.Bytes()), scanner.Offset(), scanner.Line()})
+		}
+		require.NoError(t, scanner.Err())
+		return records
+	}
+
+	t.Run("It yields multiline records with offsets and lines", func(t *testing.T) {
+		input := "id,comment\n1,\"multi\nline, \"\"quoted\"\"\"\n2,single\n3,\"last\nrecord\""
+		records := scanAll(t, New(), input)
+
+		assert.Equal(t, []record{
+			{"id,comment\n", 0, 1},
+			{"1,\"multi\nline, \"\"quoted\"\"\"\n", 11, 2},
+			{"2,single\n", 38, 4},
+			{"3,\"last\nrecord\"", 47, 5},
+		}, records)
+	})
+	t.Run("It yields records of the split file", func(t *testing.T) {
+		input, err := os.ReadFile("testdata/test.csv")
+		require.NoError(t, err)
+		s := New()
+		s.Separator = ";"
+		var joined bytes.Buffer
+		records := scanAll(t, s, string(input))
+		for _, record := range records {
+			joined.WriteString(record.data)
+		}
+
+		assert.Equal(t, string(input), joined.String())
+		assert.Len(t, records, 41)
+	})
+	t.Run("It returns errors of the source", func(t *testing.T) {
+		scanner := New().NewRecordScanner(iotest.ErrReader(assert.AnError))
+
+		assert.False(t, scanner.Scan())
+		assert.ErrorIs(t, scanner.Err(), assert.AnError)
+	})
+}
@@ -18,18 +18,110 @@ var (
 	ErrWrongSeparator     = errors.New("only one-byte separators are supported")
 	ErrSmallFileChunkSize = errors.New("file chunk size is too small")
 	ErrBigFileChunkSize   = errors.New("file chunk size is bigger than input file")
+	ErrPartsNotSupported  = errors.New("parts option is supported only by Split")
+	ErrPartsDropRecords   = errors.New("parts option can't be combined with options dropping records")
+	ErrNotSeekable        = errors.New("input should implement io.Seeker")
+	ErrHeaderConflict     = errors.New("header should be given either as fields or as raw bytes")
 )
 
 // Splitter struct which contains options for splitting
 // FileChunkSize - a size of chunk in bytes, should be set by client
 // WithHeader - whether split csv with header (true by default)
+// AllowSmallInput - whether a file which fits in one chunk is written as a single chunk by Split instead of
+// failing with ErrBigFileChunkSize, sizes of readers aren't checked
+// Header - fields of the header written to every chunk, it's added to chunks of an input without header
+// or replaces the input header, columns are referred by these names
+// RawHeader - the header line written to every chunk in the input format, it's used like Header
+// TrailerLines - a number of the last records which are stripped as a trailer
+// TrailerPattern - a regular expression, the last records matching it are stripped as a trailer
+// ChunkTrailer - a format of the trailer line written to the end of every chunk, a number of chunk rows
+// is passed to it, e.g. "TOTAL,%d" (disabled when empty)
+// Malformed - how malformed records are handled, records aren't validated by default
+// MaxRecordSize - a max size of a record in bytes when records are validated, 16 buffers (8MB) are used when 0
+// Columns - header names of columns to keep in chunks in the given order,
+// leading spaces of header fields are a part of the names
+// ColumnIndexes - indexes of columns starting from 0 to keep in chunks in the given order
+// Filter - a function deciding whether a parsed record should be kept in chunks
+// Filters - declarative filters which all should match a record to keep it in chunks
+// Transform - a function changing fields of every data record before columns selection,
+// the record is dropped when nil is returned
+// Dedup - whether duplicated records are removed, so every record appears in chunks only once,
+// it can't be combined with CheckpointPath
+// DedupColumns - header names of columns identifying duplicates, the whole record is used when empty
+// DedupMaxKeys - a max number of keys kept in memory, the rest are spilled to a file (unlimited when 0)
+// DedupSpillDir - a directory for the spill file, the default directory for temporary files is used when empty
+// InputFormat - a format of the input, it's csv by default
+// OutputFormat - a format of chunk files, chunks keep the input format by default
+// OutputSeparator - a separator of csv chunks, Separator is used when empty
+// QuoteAll - whether all fields of csv chunks are quoted
+// StrictChunkSize - whether every chunk including header should be not bigger than FileChunkSize
+// Parts - a number of chunks of roughly equal size to split the file on, FileChunkSize is derived from it.
+// It can't be combined with options dropping records: filters, Transform, Dedup, skipped or rejected
+// malformed records and TrailerPattern
+// CheckpointPath - a sidecar file where the progress is recorded after each chunk, so an interrupted
+// split can be continued with Resume (disabled when empty)
+// DryRun - whether the input is only scanned without writing chunks, rejects and checkpoints,
+// Result describes chunks which would be created. Malformed records are reported as warnings
+// and handled according to Malformed, ParseError is returned on the first one with MalformedFail
+// Checksums - algorithms of checksums computed for every chunk while it's written
+// ChecksumFiles - whether checksums are written to <chunk>.sha256 and <chunk>.md5 files in the format of sha256sum
 type Splitter struct {
-	FileChunkSize int // in bytes
-	WithHeader    bool
-	Separator     string
-	bufferSize    int // in bytes
-	fileOp        fileOperator
-	stateFactory  stateInitializer
+	FileChunkSize   int // in bytes
+	WithHeader      bool
+	Separator       string
+	Header          []string
+	RawHeader       []byte
+	TrailerLines    int
+	TrailerPattern  string
+	ChunkTrailer    string
+	Parts           int
+	AllowSmallInput bool
+	StrictChunkSize bool
+	Malformed       MalformedPolicy
+	MaxRecordSize   int
+	Columns         []string
+	ColumnIndexes   []int
+	Filter          FilterFunc
+	Filters         []RowFilter
+	Transform       TransformFunc
+	Dedup           bool
+	DedupColumns    []string
+	DedupMaxKeys    int
+	DedupSpillDir   string
+	InputFormat     Format
+	OutputFormat    Format
+	OutputSeparator string
+	QuoteAll        bool
+	CheckpointPath  string
+	DryRun          bool
+	Checksums       []Checksum
+	ChecksumFiles   bool
+	bufferSize      int // in bytes
+	plan            *partsPlan
+	singleChunk     bool       // whether the input is known to fit in one chunk
+	rolloverOnly    bool       // whether chunks are completed only when the rollover is requested
+	recordSink      recordSink // receives records instead of chunks when it's set
+	fileOp          fileOperator
+	chunkOp         fileOperator // operator of chunk files, fileOp is used when it's nil
+	stateFactory    stateInitializer
+}
+
+// Result contains details of a split
+// Chunks - paths of created chunk files
+// DroppedRows - a number of rows dropped by filters
+// RejectedRows - a number of malformed rows which were skipped or rejected
+// DuplicateRows - a number of duplicated rows which were removed
+// ChunkStats - sizes and row counts of the chunks, it's filled in the dry run
+// Warnings - parse errors of the first malformed records, it's filled in the dry run
+// Checksums - checksums of the chunks when they're enabled, chunks written before Resume aren't included
+type Result struct {
+	Chunks        []string
+	DroppedRows   int
+	RejectedRows  int
+	DuplicateRows int
+	ChunkStats    []ChunkStat
+	Warnings      []error
+	Checksums     []ChunkChecksum
 }
 
 // New initializes Splitter struct
@@ -45,98 +137,273 @@ func New() Splitter {
 
 // Split splits file in smaller chunks
 func (s Splitter) Split(inputFilePath string, outputDirPath string) ([]string, error) {
-	if len([]byte(s.Separator)) > 1 {
-		return nil, ErrWrongSeparator
+	result, err := s.SplitWithResult(inputFilePath, outputDirPath)
+	if err != nil {
+		return nil, err
 	}
-	if s.FileChunkSize < minFileChunkSize {
-		return nil, ErrSmallFileChunkSize
+
+	return result.Chunks, nil
+}
+
+// SplitWithResult splits file in smaller chunks and returns details of the split
+func (s Splitter) SplitWithResult(inputFilePath string, outputDirPath string) (*Result, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
 	}
 
 	stat, err := s.fileOp.Stat(inputFilePath)
 	if err != nil {
-		msg := fmt.Sprintf("Couldn't get file stat %s : %v", inputFilePath, err)
-		return nil, errors.New(msg)
+		return nil, &InputError{Op: "stat", Path: inputFilePath, Err: err}
 	}
 	fileSize := stat.Size()
-	if fileSize <= int64(s.FileChunkSize) {
-		return nil, ErrBigFileChunkSize
+	if s.Parts > 0 {
+		s.FileChunkSize = int(fileSize/int64(s.Parts)) + 1
+	} else if s.singleChunk, err = s.checkInputSize(fileSize); err != nil {
+		return nil, err
 	}
 
 	file, err := s.fileOp.Open(inputFilePath)
 	if err != nil {
-		msg := fmt.Sprintf("Couldn't open file %s : %v", inputFilePath, err)
-		return nil, errors.New(msg)
+		return nil, &InputError{Op: "open", Path: inputFilePath, Err: err}
 	}
 	defer file.Close()
 	fileName := getFileName(inputFilePath)
+	if s.Parts > 0 {
+		if s.plan, err = s.planParts(file); err != nil {
+			return nil, err
+		}
+	}
 
-	return s.SplitReader(file, outputDirPath, fileName)
+	return s.splitReader(file, outputDirPath, fileName, nil)
 }
 
+// SplitReader splits data from the reader in smaller chunks
 func (s Splitter) SplitReader(
 	source io.Reader,
 	outputDirPath string,
 	outputFilePrefix string,
 ) ([]string, error) {
+	result, err := s.SplitReaderWithResult(source, outputDirPath, outputFilePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Chunks, nil
+}
+
+// SplitReaderWithResult splits data from the reader in smaller chunks and returns details of the split
+func (s Splitter) SplitReaderWithResult(
+	source io.Reader,
+	outputDirPath string,
+	outputFilePrefix string,
+) (*Result, error) {
+	if s.Parts > 0 {
+		return nil, ErrPartsNotSupported
+	}
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+
+	return s.splitReader(source, outputDirPath, outputFilePrefix, nil)
+}
+
+// validate checks options of the splitter
+func (s Splitter) validate() error {
+	if len([]byte(s.Separator)) > 1 {
+		return ErrWrongSeparator
+	}
+	if s.FileChunkSize < minFileChunkSize && s.Parts <= 0 && s.recordSink == nil {
+		return ErrSmallFileChunkSize
+	}
+	if err := s.validateCheckpoint(); err != nil {
+		return err
+	}
+	if err := s.validateMalformed(); err != nil {
+		return err
+	}
+	if err := s.validateParts(); err != nil {
+		return err
+	}
+	if err := s.validateColumns(); err != nil {
+		return err
+	}
+	if err := s.validateFilters(); err != nil {
+		return err
+	}
+	if err := s.validateDedup(); err != nil {
+		return err
+	}
+	if err := s.validateFormats(); err != nil {
+		return err
+	}
+	if err := s.validateHeader(); err != nil {
+		return err
+	}
+	if err := s.validateTrailer(); err != nil {
+		return err
+	}
+	if err := s.validateChecksums(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkInputSize checks whether the input of the given size fits in one chunk and if it's allowed
+func (s Splitter) checkInputSize(size int64) (bool, error) {
+	if size > int64(s.FileChunkSize) {
+		return false, nil
+	}
+	if !s.AllowSmallInput {
+		return false, ErrBigFileChunkSize
+	}
+
+	return true, nil
+}
+
+// splitReader splits data from the reader starting from the checkpoint if it's given
+func (s Splitter) splitReader(
+	source io.Reader,
+	outputDirPath string,
+	outputFilePrefix string,
+	cp *Checkpoint,
+) (*Result, error) {
+	s = s.withDryRun()
 	bufBulk := make([]byte, s.bufferSize)
-	st := s.stateFactory.Init(
-		s,
-		outputFilePrefix,
-		prepareResultDirPath(outputDirPath),
-	)
-	isFirstBulk := true
+	st, err := s.start(outputDirPath, outputFilePrefix, cp)
+	if err != nil {
+		return nil, err
+	}
+	defer st.closeDedup()
 	for {
 		// Read bulk from file
 		size, err := source.Read(bufBulk)
+		st.offset += int64(size)
 		if err == io.EOF {
-			if _, err := st.bulkBuffer.Write(st.brokenLine); err != nil {
-				msg := fmt.Sprintf("Couldn't write brokenLine to the bulk buffer: %v", err)
-				return nil, errors.New(msg)
-			}
-			if size > 0 {
-				if _, err := st.bulkBuffer.Write(bufBulk[:size]); err != nil {
-					return nil, errors.New(
-						fmt.Sprintf("Couldn't write the last bulk to bulk buffer: %v", err),
-					)
-				}
-			}
-			err = s.saveBulkToFile(st)
-			if err != nil {
-				return nil, err
-			}
-			break
+			return s.finish(st, bufBulk[:size])
 		}
 		if err != nil {
-			msg := fmt.Sprintf("Couldn't read file bulk: %v", err)
-			return nil, errors.New(msg)
+			return nil, &InputError{Op: "read", Offset: st.offset, Err: err}
 		}
-		st.fileBuffer = bytes.NewBuffer(bufBulk[:size])
+		if err := s.consume(st, bufBulk[:size]); err != nil {
+			return nil, err
+		}
+	}
+}
 
-		if isFirstBulk {
-			st.columnsCount = countCompletedColumns(bufBulk, []byte(s.Separator)[0])
-			isFirstBulk = false
+// start initializes the state of splitting, it's continued from the checkpoint if it's given
+func (s Splitter) start(outputDirPath string, outputFilePrefix string, cp *Checkpoint) (*state, error) {
+	st := s.stateFactory.Init(
+		s,
+		outputFilePrefix,
+		prepareResultDirPath(outputDirPath),
+	)
+	st.isFirstBulk = true
+	if cp != nil {
+		st.restore(cp)
+		st.isFirstBulk = false
+	} else if s.isHeaderSupplied() {
+		if err := s.prepareHeader(st); err != nil {
+			return nil, err
 		}
+	}
 
-		lastLine, err := s.readLinesFromBulk(st)
-		if err != nil {
+	return st, nil
+}
+
+// consume splits the bulk of the input saving completed data to chunks
+func (s Splitter) consume(st *state, bulk []byte) error {
+	st.fileBuffer = bytes.NewBuffer(bulk)
+
+	if st.isFirstBulk {
+		st.columnsCount = countCompletedColumns(bulk, s.separator())
+		st.isFirstBulk = false
+	}
+
+	isLastLineBroken := false
+	var err error
+	if s.isRecordMode() {
+		err = s.readRecordsFromBulk(st)
+	} else {
+		var lastLine []byte
+		lastLine, err = s.readLinesFromBulk(st)
+		isLastLineBroken = isBrokenMultiLine(lastLine, st)
+	}
+	if err != nil {
+		return err
+	}
+	// If there is nothing to write to the file or the last line is broken multiline row or file chunk size is less
+	// than a buffer size and bulk buffer is smaller than file chunk size then skip saving bulk to file and read
+	// the next file bulk.
+	if st.bulkBuffer.Len() == 0 || isLastLineBroken ||
+		(st.s.FileChunkSize < st.s.bufferSize && !st.isBulkBufferBiggerOrEqualsFileChunkSize()) {
+		return nil
+	}
+
+	return s.saveBulkToFile(st)
+}
+
+// finish splits the last bulk of the input, completes the last chunk and returns details of the split
+func (s Splitter) finish(st *state, lastBulk []byte) (*Result, error) {
+	if s.isRecordMode() {
+		if st.isFirstBulk {
+			st.columnsCount = countCompletedColumns(lastBulk, s.separator())
+		}
+		if err := s.finishRecords(st, lastBulk); err != nil {
 			return nil, err
 		}
-		// If there is nothing to write to the file or the last line is broken multiline row or file chunk size is less
-		// than a buffer size and bulk buffer is smaller than file chunk size then skip saving bulk to file and read
-		// the next file bulk.
-		if st.bulkBuffer.Len() == 0 || isBrokenMultiLine(lastLine, st) ||
-			(st.s.FileChunkSize < st.s.bufferSize && !st.isBulkBufferBiggerOrEqualsFileChunkSize()) {
-			continue
+	} else if err := s.finishLines(st, lastBulk); err != nil {
+		return nil, err
+	}
+	if st.chunkSize > 0 {
+		if err := s.writeChunkTrailer(st); err != nil {
+			return nil, err
 		}
+	}
+	if err := s.saveChecksums(st); err != nil {
+		return nil, err
+	}
+	if st.chunkFile != nil {
+		st.chunkFile.Close()
+	}
+	st.closeRejects()
+	if err := s.saveCheckpoint(st, true); err != nil {
+		return nil, err
+	}
 
-		err = s.saveBulkToFile(st)
-		if err != nil {
-			return nil, err
+	result := &Result{
+		Chunks:        st.result,
+		DroppedRows:   st.dropped,
+		RejectedRows:  st.rejected,
+		DuplicateRows: st.duplicates,
+		Checksums:     st.checksums,
+	}
+	if s.DryRun {
+		result.ChunkStats = s.chunkStats(st)
+		result.Warnings = st.warnings
+	}
+
+	return result, nil
+}
+
+// finishLines writes the last bulk and the rest of data to the last chunk
+func (s Splitter) finishLines(st *state, lastBulk []byte) error {
+	if _, err := st.bulkBuffer.Write(st.brokenLine); err != nil {
+		return fmt.Errorf("Couldn't write brokenLine to the bulk buffer: %w", err)
+	}
+	st.chunkRecords += s.countRecords(st, append(st.brokenLine, lastBulk...))
+	st.brokenLine = nil
+	if len(lastBulk) > 0 {
+		if _, err := st.bulkBuffer.Write(lastBulk); err != nil {
+			return fmt.Errorf("Couldn't write the last bulk to bulk buffer: %w", err)
 		}
 	}
-	st.chunkFile.Close()
+	// Don't create an empty chunk when all the data has been already saved
+	if len(st.result) > 0 && st.bulkBuffer.Len() == 0 {
+		return nil
+	}
 
-	return st.result, nil
+	return s.saveBulkToFile(st)
 }
 
 // readLinesFromBulk reads bulk line by line
@@ -153,25 +420,36 @@ func (s Splitter) readLinesFromBulk(st *state) ([]byte, error) {
 			break
 		}
 		if err != nil {
-			msg := fmt.Sprintf(
-				"Couldn't read bytes from buffer: %v",
-				err,
-			)
-			return nil, errors.New(msg)
+			return nil, fmt.Errorf("Couldn't read bytes from buffer: %w", err)
 		}
 		lastLine = bytesLine
-		if st.isFirstLine && st.s.WithHeader {
-			st.header = append(st.header, bytesLine...)
+		if st.isFirstLine && st.s.hasHeader() {
+			if !s.isHeaderSupplied() {
+				st.header = append(st.header, bytesLine...)
+			}
 			if !isBrokenMultiLine(bytesLine, st) {
 				st.isFirstLine = false
 			}
 			continue
 		}
 		if _, err := st.bulkBuffer.Write(bytesLine); err != nil {
-			msg := fmt.Sprintf("Couldn't write to the bulk buffer: %v", err)
-			return nil, errors.New(msg)
+			return nil, fmt.Errorf("Couldn't write to the bulk buffer: %w", err)
+		}
+		if s.plan == nil {
+			if st.isBulkBufferBiggerOrEqualsFileChunkSize() && !isBrokenMultiLine(bytesLine, st) {
+				if err = s.saveBulkToFile(st); err != nil {
+					return nil, err
+				}
+			}
+			continue
 		}
-		if st.isBulkBufferBiggerOrEqualsFileChunkSize() && !isBrokenMultiLine(bytesLine, st) {
+		// records are counted only to distribute them between parts
+		isBroken := isBrokenMultiLine(bytesLine, st)
+		st.recordSize += int64(len(bytesLine))
+		if !isBroken {
+			st.completeRecord()
+		}
+		if (st.isBulkBufferBiggerOrEqualsFileChunkSize() || st.rollover) && !isBroken {
 			if err = s.saveBulkToFile(st); err != nil {
 				return nil, err
 			}
@@ -182,7 +460,10 @@ func (s Splitter) readLinesFromBulk(st *state) ([]byte, error) {
 }
 
 func isBrokenMultiLine(bytesLine []byte, st *state) bool {
-	separator := []byte(st.s.Separator)[0]
+	if st.s.inputFormat() != FormatCSV {
+		return false
+	}
+	separator := st.s.separator()
 
 	return countCompletedColumns(bytesLine, separator) != st.columnsCount &&
 		!isCompletingLine(bytesLine, separator)
@@ -276,31 +557,51 @@ loop:
 
 // saveBulkToFile saves lines from bulk to a new file
 func (s Splitter) saveBulkToFile(st *state) error {
-	st.chunkFilePath = fmt.Sprintf("%s%s_%d.csv", st.resultDirPath, st.fileName, st.chunk)
-	stat, err := s.fileOp.Stat(st.chunkFilePath)
-	if s.fileOp.IsNotExist(err) {
-		chunkFile, err := s.fileOp.Create(st.chunkFilePath)
+	st.chunkFilePath = fmt.Sprintf("%s%s_%d.%s", st.resultDirPath, st.fileName, st.chunk, extensions[s.outputFormat()])
+	stat, err := s.chunkOperator().Stat(st.chunkFilePath)
+	if s.chunkOperator().IsNotExist(err) || st.chunkFile == nil {
+		if size := len(st.header) + s.trailerSize(0); s.StrictChunkSize && size > s.FileChunkSize {
+			return &HeaderSizeError{Size: size, Limit: s.FileChunkSize}
+		}
+		chunkFile, err := s.chunkOperator().Create(st.chunkFilePath)
 		if err != nil {
-			msg := fmt.Sprintf("Couldn't create file %s: %v", st.chunkFilePath, err)
-			return errors.New(msg)
+			return &ChunkWriteError{Op: "create", Path: st.chunkFilePath, Chunk: st.chunk, Err: err}
 		}
-		st.setChunkFile(chunkFile)
+		st.setChunkFile(s.trackChecksums(st, chunkFile))
+		st.chunkSize = int64(len(st.header))
 		_, err = st.chunkFile.Write(st.header)
 		if err != nil {
-			msg := fmt.Sprintf("Couldn't write header of chunk file %s : %v", st.chunkFilePath, err)
-			return errors.New(msg)
+			return &ChunkWriteError{
+				Op:    "write header",
+				Path:  st.chunkFilePath,
+				Chunk: st.chunk,
+				Err:   err,
+			}
 		}
 		st.result = append(st.result, st.chunkFilePath)
 	}
 	bytes := st.bulkBuffer.Bytes()
 	_, err = st.chunkFile.Write(bytes)
 	if err != nil {
-		msg := fmt.Sprintf("Couldn't write chunk file %s : %v", st.chunkFilePath, err)
-		return errors.New(msg)
+		return &ChunkWriteError{Op: "write", Path: st.chunkFilePath, Chunk: st.chunk, Err: err}
 	}
-	stat, _ = s.fileOp.Stat(st.chunkFilePath)
-	if stat.Size() > int64(st.s.FileChunkSize-st.s.bufferSize) {
+	st.chunkSize += int64(len(bytes))
+	stat, _ = s.chunkOperator().Stat(st.chunkFilePath)
+	if st.isChunkCompleted(stat.Size()) {
+		if err := s.writeChunkTrailer(st); err != nil {
+			return err
+		}
+		if err := s.saveChecksums(st); err != nil {
+			return err
+		}
+		st.rollover = false
+		st.chunkRows = append(st.chunkRows, st.chunkRecords)
 		st.chunk++
+		st.chunkSize = 0
+		st.chunkRecords = 0
+		if err := s.saveCheckpoint(st, false); err != nil {
+			return err
+		}
 	}
 	st.bulkBuffer.Reset()
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -209,6 +210,21 @@ func Test_Split_integration(t *testing.T) {
 		assert.Nil(t, result)
 		assert.Equal(t, err, errors.New("file chunk size is bigger than input file"))
 	})
+	t.Run("Small input as a single chunk", func(t *testing.T) {
+		s := New()
+		s.Separator = ";"
+		s.FileChunkSize = 1000000
+		s.bufferSize = 100
+		s.AllowSmallInput = true
+		dir := t.TempDir()
+		result, err := s.Split(input, dir)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{dir + "/test_1.csv"}, result)
+		expected, _ := os.ReadFile(input)
+		actual, _ := os.ReadFile(dir + "/test_1.csv")
+		assert.Equal(t, string(expected), string(actual))
+	})
 	t.Run("Small file chunk error", func(t *testing.T) {
 		s := New()
 		s.Separator = ";"
@@ -414,6 +430,76 @@ func Test_SplitReader_integration(t *testing.T) {
 	})
 }
 
+func Test_SplitReader_smallInput(t *testing.T) {
+	data := "Test header 1; Test header 2\n1; test value 1st\n2; test value 2nd\n"
+	t.Run("Small input of any reader isn't checked", func(t *testing.T) {
+		readers := map[string]io.Reader{
+			"sized":   strings.NewReader(data),
+			"unsized": io.MultiReader(strings.NewReader(data)),
+		}
+		for name, reader := range readers {
+			s := New()
+			s.Separator = ";"
+			s.FileChunkSize = 400
+			dir := t.TempDir()
+			result, err := s.SplitReader(reader, dir, "test")
+
+			assert.Nil(t, err, name)
+			assert.Equal(t, []string{dir + "/test_1.csv"}, result, name)
+			actual, _ := os.ReadFile(dir + "/test_1.csv")
+			assert.Equal(t, data, string(actual), name)
+		}
+	})
+	t.Run("Small input as a single chunk", func(t *testing.T) {
+		s := New()
+		s.Separator = ";"
+		s.FileChunkSize = 400
+		s.AllowSmallInput = true
+		dir := t.TempDir()
+		result, err := s.SplitReader(strings.NewReader(data), dir, "test")
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{dir + "/test_1.csv"}, result)
+		actual, _ := os.ReadFile(dir + "/test_1.csv")
+		assert.Equal(t, data, string(actual))
+	})
+	t.Run("Small input keeps the strict chunk size", func(t *testing.T) {
+		input := "id,name\n" + strings.Repeat("1,test value\n", 6) + "7,last value\n"
+		s := New()
+		s.FileChunkSize = 100
+		s.AllowSmallInput = true
+		s.StrictChunkSize = true
+		s.ChunkTrailer = "TOTAL,%d"
+		result, err := s.SplitReader(strings.NewReader(input), t.TempDir(), "test")
+
+		assert.Nil(t, err)
+		assert.Len(t, result, 2)
+		for _, chunk := range result {
+			stat, _ := os.Stat(chunk)
+			assert.LessOrEqual(t, stat.Size(), int64(100))
+		}
+	})
+	t.Run("Wrong separator", func(t *testing.T) {
+		s := New()
+		s.Separator = "Ω"
+		s.FileChunkSize = 400
+		result, err := s.SplitReader(strings.NewReader(data), t.TempDir(), "test")
+
+		assert.Nil(t, result)
+		assert.Equal(t, ErrWrongSeparator, err)
+	})
+}
+
+// newTestSplitter creates a splitter of small chunks which accepts inputs fitting in one chunk
+func newTestSplitter(separator string, fileChunkSize int) Splitter {
+	s := New()
+	s.Separator = separator
+	s.FileChunkSize = fileChunkSize
+	s.AllowSmallInput = true
+
+	return s
+}
+
 func assertResult(t *testing.T, result []string, expected []string) {
 	for i, item := range expected {
 		if i == 3 {
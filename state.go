@@ -1,23 +1,60 @@
 package split_csv
 
 import (
+	"encoding/csv"
 	"io"
+	"regexp"
 )
 
 type state struct {
-	s             Splitter
-	fileName      string
-	resultDirPath string
-	chunkFile     io.WriteCloser
-	chunkFilePath string
-	header        []byte
-	isFirstLine   bool
-	brokenLine    []byte
-	chunk         int
-	bulkBuffer    buffer // to buffer a bulk to be stored as a chunk file
-	fileBuffer    buffer // to buffer a chunk of the input file
-	columnsCount  int
-	result        []string
+	s              Splitter
+	fileName       string
+	resultDirPath  string
+	chunkFile      io.WriteCloser
+	chunkFilePath  string
+	header         []byte
+	isFirstLine    bool
+	isFirstBulk    bool
+	brokenLine     []byte
+	chunk          int
+	bulkBuffer     buffer // to buffer a bulk to be stored as a chunk file
+	fileBuffer     buffer // to buffer a chunk of the input file
+	columnsCount   int
+	result         []string
+	offset         int64  // number of bytes read from the source
+	records        int    // number of completed data records
+	dataSize       int64  // size of completed data records
+	recordSize     int64  // size of the record which is being read
+	rollover       bool   // whether the chunk should be completed on the next save
+	record         []byte // lines of the record which is being read in the record mode
+	pending        []byte // the record which is being written in the record mode
+	chunkSize      int64  // number of bytes written to the current chunk file
+	line           int    // number of lines read in the record mode
+	lineOffset     int64  // offset of the next line in the record mode
+	recordLine     int    // number of the first line of the record which is being read
+	recordQuotes   int    // number of quotes in the record which is being read
+	fieldsCount    int    // number of fields in the first record
+	rejected       int    // number of malformed records
+	rejects        *csv.Writer
+	rejectsFile    io.WriteCloser
+	rejectsPath    string
+	columns        []int          // indexes of the selected columns
+	columnsChecked bool           // whether the selected columns have been checked on a record
+	headerFields   []string       // parsed fields of the input header
+	headerMap      map[string]int // positions of the input header fields
+	outputKeys     []string       // names of the output fields
+	filters        []filter
+	dropped        int // number of records dropped by filters
+	dedup          *dedupSet
+	dedupColumns   []int // indexes of columns identifying duplicates
+	duplicates     int   // number of removed duplicated records
+	held           []heldRecord
+	trailerPattern *regexp.Regexp
+	chunkRecords   int   // number of records written to the current chunk
+	chunkRows      []int // numbers of records of the completed chunks
+	warnings       []error
+	checksum       *checksumWriter // computes checksums of the current chunk
+	checksums      []ChunkChecksum
 }
 
 func (s *state) setChunkFile(file io.WriteCloser) {
@@ -27,6 +64,84 @@ func (s *state) setChunkFile(file io.WriteCloser) {
 	s.chunkFile = file
 }
 
+// headerPositions returns positions of the input header fields by their names,
+// the first one is used for duplicated names
+func (s *state) headerPositions() map[string]int {
+	if s.headerMap == nil && s.headerFields != nil {
+		s.headerMap = make(map[string]int, len(s.headerFields))
+		for i := len(s.headerFields) - 1; i >= 0; i-- {
+			s.headerMap[s.headerFields[i]] = i
+		}
+	}
+
+	return s.headerMap
+}
+
+// outputHeader returns names of fields in the output records, nil is returned when the input has no header
+func (s *state) outputHeader() []string {
+	if s.outputKeys == nil && s.headerFields != nil {
+		s.outputKeys = s.headerFields
+		if s.s.isProjecting() {
+			s.outputKeys = projectFields(s.headerFields, s.columns)
+		}
+	}
+
+	return s.outputKeys
+}
+
+// closeRejects closes the file with rejected records if it has been created
+func (s *state) closeRejects() {
+	if s.rejectsFile != nil {
+		s.rejectsFile.Close()
+		s.rejectsFile = nil
+	}
+}
+
+// closeDedup removes the deduplication spill file if it has been created
+func (s *state) closeDedup() {
+	if s.dedup != nil {
+		s.dedup.close()
+	}
+}
+
+// isChunkCompleted checks whether the chunk of the given size shouldn't receive more data
+func (s *state) isChunkCompleted(size int64) bool {
+	if s.s.plan != nil || s.s.StrictChunkSize || s.s.rolloverOnly {
+		return s.rollover
+	}
+
+	return !s.s.singleChunk && size > int64(s.s.FileChunkSize-s.s.bufferSize)
+}
+
+// completeRecord accounts the record which has been just read
+func (s *state) completeRecord() {
+	s.records++
+	s.chunkRecords++
+	s.dataSize += s.recordSize
+	s.recordSize = 0
+	if s.s.plan != nil && s.s.plan.isChunkCompleted(s.chunk, s.records, s.dataSize) {
+		s.rollover = true
+	}
+}
+
 func (s *state) isBulkBufferBiggerOrEqualsFileChunkSize() bool {
 	return s.bulkBuffer.Len() >= (s.s.FileChunkSize - len(s.header))
 }
+
+// restore continues the state from the checkpoint
+func (s *state) restore(cp *Checkpoint) {
+	s.offset = cp.Offset
+	s.chunk = cp.Chunk
+	s.header = cp.Header
+	s.isFirstLine = false
+	s.brokenLine = cp.BrokenLine
+	s.columnsCount = cp.ColumnsCount
+	s.result = cp.Result
+	s.line = cp.Line
+	s.lineOffset = cp.Offset
+	s.fieldsCount = cp.FieldsCount
+	s.columns = cp.Columns
+	s.headerFields = cp.HeaderFields
+	s.dropped = cp.DroppedRows
+	s.rejected = cp.RejectedRows
+}
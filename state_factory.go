@@ -20,7 +20,7 @@ func (f stateFactory) Init(
 	resultDirPath string,
 ) *state {
 	var header []byte
-	if s.WithHeader {
+	if s.hasHeader() {
 		header = make([]byte, 0)
 	}
 
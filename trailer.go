@@ -0,0 +1,104 @@
+package split_csv
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+var (
+	ErrTrailerLines        = errors.New("number of trailer lines should not be negative")
+	ErrTrailerPattern      = errors.New("wrong trailer pattern")
+	ErrTrailerNotResumable = errors.New("trailer records can't be stripped with checkpoints")
+)
+
+// heldRecord is a record which can be a part of the trailer
+type heldRecord struct {
+	record []byte
+	fields []string
+	line   int   // number of the first line of the record
+	offset int64 // offset of the record in the source
+}
+
+// isStrippingTrailer checks whether trailer records should be stripped
+func (s Splitter) isStrippingTrailer() bool {
+	return s.TrailerLines > 0 || s.TrailerPattern != ""
+}
+
+// validateTrailer checks options of the trailer
+func (s Splitter) validateTrailer() error {
+	if s.TrailerLines < 0 {
+		return fmt.Errorf("%w: %d", ErrTrailerLines, s.TrailerLines)
+	}
+	if _, err := regexp.Compile(s.TrailerPattern); err != nil {
+		return fmt.Errorf("%w: %w", ErrTrailerPattern, err)
+	}
+	if s.isStrippingTrailer() && s.CheckpointPath != "" {
+		return ErrTrailerNotResumable
+	}
+
+	return nil
+}
+
+// holdRecord keeps the record until it's known that it isn't a part of the trailer
+// and processes the records which can't be a part of the trailer anymore
+func (s Splitter) holdRecord(st *state, record []byte, fields []string) error {
+	st.held = append(st.held, heldRecord{
+		record: record,
+		fields: fields,
+		line:   st.recordLine,
+		offset: st.lineOffset - int64(len(record)),
+	})
+	if s.TrailerPattern != "" {
+		if st.trailerPattern == nil {
+			st.trailerPattern = regexp.MustCompile(s.TrailerPattern)
+		}
+		if st.trailerPattern.Match(bytes.TrimRight(record, "\r\n")) {
+			return nil
+		}
+	}
+	line, lineOffset := st.recordLine, st.lineOffset
+	defer func() {
+		st.recordLine, st.lineOffset = line, lineOffset
+	}()
+	for len(st.held) > s.TrailerLines {
+		held := st.held[0]
+		st.held = st.held[1:]
+		st.recordLine = held.line
+		st.lineOffset = held.offset + int64(len(held.record))
+		st.recordSize = int64(len(held.record))
+		if err := s.processRecord(st, held.record, held.fields); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// trailerSize returns a size of the chunk trailer with the given number of rows
+func (s Splitter) trailerSize(rows int) int {
+	if s.ChunkTrailer == "" {
+		return 0
+	}
+
+	return len(s.formatTrailer(rows))
+}
+
+// formatTrailer returns the chunk trailer with the given number of rows
+func (s Splitter) formatTrailer(rows int) []byte {
+	return []byte(fmt.Sprintf(s.ChunkTrailer, rows) + "\n")
+}
+
+// writeChunkTrailer writes the trailer with the number of rows to the current chunk if it's enabled
+func (s Splitter) writeChunkTrailer(st *state) error {
+	if s.ChunkTrailer == "" || st.chunkFile == nil {
+		return nil
+	}
+	trailer := s.formatTrailer(st.chunkRecords)
+	if _, err := st.chunkFile.Write(trailer); err != nil {
+		return &ChunkWriteError{Op: "write trailer", Path: st.chunkFilePath, Chunk: st.chunk, Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package split_csv
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SplitTrailer_integration(t *testing.T) {
+	t.Run("It strips the last records", func(t *testing.T) {
+		input := "id,name\n1,\"multi\nline\"\n2,second\nTOTAL,2\nEND\n"
+		s := newTestSplitter(",", 100)
+		s.TrailerLines = 2
+		result, err := s.SplitReader(strings.NewReader(input), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		assert.Equal(t, "id,name\n1,\"multi\nline\"\n2,second\n", readChunks(t, result))
+	})
+	t.Run("It strips the last records matching the pattern", func(t *testing.T) {
+		input := "id,name\nTOTAL,first\n2,second\nTOTAL,2\nTOTAL,3"
+		s := newTestSplitter(",", 100)
+		s.TrailerPattern = "^TOTAL,"
+		result, err := s.SplitReader(strings.NewReader(input), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		assert.Equal(t, "id,name\nTOTAL,first\n2,second\n", readChunks(t, result))
+	})
+	t.Run("It writes trailers with a number of chunk rows", func(t *testing.T) {
+		var input strings.Builder
+		input.WriteString("id,value\n")
+		for i := 1; i <= 30; i++ {
+			fmt.Fprintf(&input, "%d,value\n", i)
+		}
+		s := newTestSplitter(",", 100)
+		s.StrictChunkSize = true
+		s.ChunkTrailer = "TOTAL,%d"
+		result, err := s.SplitReader(strings.NewReader(input.String()), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		require.Greater(t, len(result), 1)
+		rows := 0
+		for _, chunk := range result {
+			content, err := os.ReadFile(chunk)
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(content), s.FileChunkSize)
+			lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+			assert.Equal(t, "id,value", lines[0])
+			assert.Equal(t, fmt.Sprintf("TOTAL,%d", len(lines)-2), lines[len(lines)-1])
+			rows += len(lines) - 2
+		}
+		assert.Equal(t, 30, rows)
+	})
+	t.Run("It writes the trailer on a new line", func(t *testing.T) {
+		s := newTestSplitter(",", 100)
+		s.ChunkTrailer = "TOTAL,%d"
+		result, err := s.SplitReader(strings.NewReader("id\n1\n2"), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		assert.Equal(t, "id\n1\n2\nTOTAL,2\n", readChunks(t, result))
+	})
+	t.Run("It doesn't strip trailer with checkpoints", func(t *testing.T) {
+		s := newTestSplitter(",", 100)
+		s.TrailerLines = 1
+		s.CheckpointPath = t.TempDir() + "/checkpoint.json"
+		_, err := s.SplitReader(strings.NewReader("id\n1\n"), t.TempDir(), "test")
+
+		assert.ErrorIs(t, err, ErrTrailerNotResumable)
+	})
+}
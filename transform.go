@@ -0,0 +1,5 @@
+package split_csv
+
+// TransformFunc changes fields of the record, the record is dropped when nil is returned.
+// Fields are quoted again when the record is written to a chunk.
+type TransformFunc func(record []string) ([]string, error)
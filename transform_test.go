@@ -0,0 +1,60 @@
+package split_csv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SplitTransform_integration(t *testing.T) {
+	input := "id;name;card\n1; \"Smith; John\";4111111111111111\n2;Doe;5500000000000004\n3;skip;0\n"
+	t.Run("It transforms records and quotes them again", func(t *testing.T) {
+		s := newTestSplitter(";", 100)
+		s.Transform = func(record []string) ([]string, error) {
+			if record[1] == "skip" {
+				return nil, nil
+			}
+			record[1] = strings.ReplaceAll(record[1], "; ", ";\n")
+			record[2] = "************" + record[2][12:]
+			return record, nil
+		}
+		result, err := s.SplitReaderWithResult(strings.NewReader(input), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			"id;name;card\n1;\"Smith;\nJohn\";************1111\n2;Doe;************0004\n",
+			readChunks(t, result.Chunks),
+		)
+		assert.Equal(t, 1, result.DroppedRows)
+	})
+	t.Run("It transforms records before columns selection", func(t *testing.T) {
+		s := newTestSplitter(";", 100)
+		s.Transform = func(record []string) ([]string, error) {
+			return append(record, "extra"), nil
+		}
+		s.ColumnIndexes = []int{3, 0}
+		result, err := s.SplitReader(strings.NewReader(input), t.TempDir(), "test")
+
+		require.NoError(t, err)
+		assert.Equal(t, ";id\nextra;1\nextra;2\nextra;3\n", readChunks(t, result))
+	})
+	t.Run("It fails when the transformation fails", func(t *testing.T) {
+		transformErr := errors.New("wrong card")
+		s := newTestSplitter(";", 100)
+		s.Transform = func(record []string) ([]string, error) {
+			return nil, transformErr
+		}
+		result, err := s.SplitReader(strings.NewReader(input), t.TempDir(), "test")
+
+		assert.Nil(t, result)
+		var recordErr *TransformError
+		require.True(t, errors.As(err, &recordErr))
+		assert.Equal(t, 2, recordErr.Line)
+		assert.Equal(t, int64(13), recordErr.Offset)
+		assert.ErrorIs(t, err, transformErr)
+	})
+}
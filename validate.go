@@ -0,0 +1,150 @@
+package split_csv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// MalformedPolicy defines how malformed records are handled
+type MalformedPolicy int
+
+const (
+	// MalformedIgnore disables validation of records
+	MalformedIgnore MalformedPolicy = iota
+	// MalformedFail stops splitting with ParseError on the first malformed record
+	MalformedFail
+	// MalformedSkip drops malformed records
+	MalformedSkip
+	// MalformedReject writes malformed records to <prefix>_rejects.csv with a line number and a reason
+	MalformedReject
+)
+
+// defaultMaxRecordBulks is a max size of a record in bulks when MaxRecordSize isn't set
+const defaultMaxRecordBulks = 16
+
+var (
+	ErrUnbalancedQuotes = errors.New("unbalanced quotes")
+	ErrFieldsCount      = errors.New("wrong number of fields")
+	ErrRecordTooLong    = errors.New("record is too long")
+	// ErrRejectsNotResumable is returned because the rejects file is created anew when the split is resumed
+	ErrRejectsNotResumable = errors.New("rejected records can't be written with checkpoints")
+)
+
+// isValidating checks whether records should be validated, they're always validated in the dry run
+func (s Splitter) isValidating() bool {
+	return s.Malformed != MalformedIgnore || s.DryRun
+}
+
+// validateMalformed checks options of the malformed records handling
+func (s Splitter) validateMalformed() error {
+	if s.Malformed == MalformedReject && s.CheckpointPath != "" {
+		return ErrRejectsNotResumable
+	}
+
+	return nil
+}
+
+// maxRecordSize returns the max size of a record in the validation mode,
+// so an unterminated quote doesn't make the rest of the input buffered as one record
+func (s Splitter) maxRecordSize() int {
+	if s.MaxRecordSize > 0 {
+		return s.MaxRecordSize
+	}
+
+	return defaultMaxRecordBulks * s.bufferSize
+}
+
+// validateRecord checks the number of fields and the size of the completed record and returns its fields
+func (s Splitter) validateRecord(st *state, record []byte) ([]string, error) {
+	fields, err := s.parseFields(record)
+	if err != nil {
+		return nil, err
+	}
+	if st.fieldsCount == 0 {
+		st.fieldsCount = len(fields)
+	}
+	if len(fields) != st.fieldsCount {
+		return nil, fmt.Errorf("%w: expected %d, got %d", ErrFieldsCount, st.fieldsCount, len(fields))
+	}
+	if len(record) > s.maxRecordSize() {
+		return nil, ErrRecordTooLong
+	}
+
+	return fields, nil
+}
+
+// recoverRecord handles the first line of the incomplete record as a malformed record
+// and reads the rest of its lines again
+func (s Splitter) recoverRecord(st *state, reason error) error {
+	record := st.record
+	st.record = nil
+	offset := st.lineOffset - int64(len(record))
+	end := bytes.IndexByte(record, '\n') + 1
+	if end == 0 {
+		end = len(record)
+	}
+	st.line = st.recordLine
+	st.lineOffset = offset + int64(end)
+	if err := s.handleMalformed(st, record[:end], st.recordLine, offset, reason); err != nil {
+		return err
+	}
+	for _, line := range bytes.SplitAfter(record[end:], []byte{'\n'}) {
+		if len(line) == 0 {
+			continue
+		}
+		if err := s.readRecordLine(st, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handleMalformed applies the malformed records policy to the record
+func (s Splitter) handleMalformed(st *state, record []byte, line int, offset int64, reason error) error {
+	if s.Malformed == MalformedFail {
+		return &ParseError{Offset: offset, Line: line, Err: reason}
+	}
+	if s.DryRun {
+		// records are validated in the dry run only to report them, the split keeps them with MalformedIgnore
+		st.warn(&ParseError{Offset: offset, Line: line, Err: reason})
+		if s.Malformed == MalformedIgnore {
+			return s.handleRecord(st, record, nil)
+		}
+	}
+	st.rejected++
+	if s.Malformed != MalformedReject || s.DryRun {
+		return nil
+	}
+	if st.rejects == nil {
+		path := fmt.Sprintf("%s%s_rejects.csv", st.resultDirPath, st.fileName)
+		file, err := s.fileOp.Create(path)
+		if err != nil {
+			return &ChunkWriteError{Op: "create", Path: path, Err: err}
+		}
+		st.rejectsFile = file
+		st.rejectsPath = path
+		st.rejects = csv.NewWriter(file)
+		st.rejects.Comma = rune(s.separator())
+		st.rejects.Write([]string{"line", "reason", "record"})
+	}
+	st.rejects.Write([]string{
+		strconv.Itoa(line),
+		reason.Error(),
+		string(bytes.TrimRight(record, "\r\n")),
+	})
+	st.rejects.Flush()
+	if err := st.rejects.Error(); err != nil {
+		return &ChunkWriteError{Op: "write", Path: st.rejectsPath, Err: err}
+	}
+
+	return nil
+}
+
+// isMultiLine checks whether the record contains more than one line
+func isMultiLine(record []byte) bool {
+	return bytes.IndexByte(bytes.TrimRight(record, "\r\n"), '\n') >= 0
+}
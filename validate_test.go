@@ -0,0 +1,145 @@
+package split_csv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const malformedInput = `id;name;value
+1;first;10
+2;second;20;extra
+3;"third;30
+4;fourth;40
+5;"fifth
+line";50
+6;sixth;60
+`
+
+func Test_SplitMalformed_integration(t *testing.T) {
+	t.Run("It fails on the first malformed record", func(t *testing.T) {
+		s := newTestSplitter(";", 100)
+		s.Malformed = MalformedFail
+		s.MaxRecordSize = 40
+		result, err := s.SplitReader(strings.NewReader(malformedInput), t.TempDir(), "test")
+
+		assert.Nil(t, result)
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr))
+		assert.Equal(t, 3, parseErr.Line)
+		assert.Equal(t, int64(25), parseErr.Offset)
+		assert.ErrorIs(t, err, ErrFieldsCount)
+	})
+	t.Run("It skips malformed records", func(t *testing.T) {
+		dir := t.TempDir()
+		s := newTestSplitter(";", 100)
+		s.Malformed = MalformedSkip
+		s.MaxRecordSize = 40
+		result, err := s.SplitReader(strings.NewReader(malformedInput), dir, "test")
+
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			"id;name;value\n1;first;10\n4;fourth;40\n5;\"fifth\nline\";50\n6;sixth;60\n",
+			readChunks(t, result),
+		)
+		assert.NoFileExists(t, filepath.Join(dir, "test_rejects.csv"))
+	})
+	t.Run("It writes malformed records to the rejects file", func(t *testing.T) {
+		dir := t.TempDir()
+		s := newTestSplitter(";", 100)
+		s.Malformed = MalformedReject
+		s.MaxRecordSize = 40
+		result, err := s.SplitReader(strings.NewReader(malformedInput), dir, "test")
+
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			"id;name;value\n1;first;10\n4;fourth;40\n5;\"fifth\nline\";50\n6;sixth;60\n",
+			readChunks(t, result),
+		)
+		rejects, err := os.ReadFile(filepath.Join(dir, "test_rejects.csv"))
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			"line;reason;record\n"+
+				"3;wrong number of fields: expected 3, got 4;\"2;second;20;extra\"\n"+
+				"4;unbalanced quotes;\"3;\"\"third;30\"\n",
+			string(rejects),
+		)
+	})
+	t.Run("It limits the size of a record with an unterminated quote", func(t *testing.T) {
+		dir := t.TempDir()
+		s := newTestSplitter(";", 100)
+		s.Malformed = MalformedReject
+		s.MaxRecordSize = 30
+		input := "id;name;value\n1;\"first;10\n2;second;20\n3;third;30\n4;fourth;40\n"
+		result, err := s.SplitReader(strings.NewReader(input), dir, "test")
+
+		require.NoError(t, err)
+		assert.Equal(t, "id;name;value\n2;second;20\n3;third;30\n4;fourth;40\n", readChunks(t, result))
+		rejects, _ := os.ReadFile(filepath.Join(dir, "test_rejects.csv"))
+		assert.Equal(t, "line;reason;record\n2;record is too long;\"1;\"\"first;10\"\n", string(rejects))
+	})
+	t.Run("It fails on unbalanced quotes in the end of input", func(t *testing.T) {
+		s := newTestSplitter(";", 100)
+		s.Malformed = MalformedFail
+		_, err := s.SplitReader(strings.NewReader("id;name\n1;\"first\n2;second\n"), t.TempDir(), "test")
+
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr))
+		assert.Equal(t, 2, parseErr.Line)
+		assert.ErrorIs(t, err, ErrUnbalancedQuotes)
+	})
+	t.Run("It limits the size of a record by default", func(t *testing.T) {
+		s := newTestSplitter(";", 100)
+		s.Malformed = MalformedFail
+		s.bufferSize = 4
+		input := "id;name\n1;\"first\n" + strings.Repeat("2;second\n", 10)
+		_, err := s.SplitReader(strings.NewReader(input), t.TempDir(), "test")
+
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr))
+		assert.Equal(t, 2, parseErr.Line)
+		assert.ErrorIs(t, err, ErrRecordTooLong)
+	})
+	t.Run("It doesn't write rejects with checkpoints", func(t *testing.T) {
+		s := newTestSplitter(";", 100)
+		s.Malformed = MalformedReject
+		s.MaxRecordSize = 40
+		s.CheckpointPath = filepath.Join(t.TempDir(), "checkpoint.json")
+		_, err := s.SplitReader(strings.NewReader(malformedInput), t.TempDir(), "test")
+
+		assert.ErrorIs(t, err, ErrRejectsNotResumable)
+	})
+	t.Run("It keeps valid multiline cells", func(t *testing.T) {
+		s := New()
+		s.Separator = ";"
+		s.FileChunkSize = 800
+		s.Malformed = MalformedFail
+		input := "testdata/test_multiline_cells.csv"
+		result, err := s.Split(input, t.TempDir())
+
+		require.NoError(t, err)
+		source, _ := os.ReadFile(input)
+		header := source[:strings.Index(string(source), "5\n")+2]
+		assert.Equal(t, string(source), string(joinChunks(t, result, header)))
+	})
+}
+
+// readChunks concatenates content of all the chunks
+func readChunks(t *testing.T, chunks []string) string {
+	var result []byte
+	for _, chunk := range chunks {
+		content, err := os.ReadFile(chunk)
+		require.NoError(t, err)
+		result = append(result, content...)
+	}
+
+	return string(result)
+}
@@ -0,0 +1,89 @@
+package split_csv
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Verify checks that the chunks joined like Join does reproduce the input file exactly.
+// The chunks and the input are streamed and compared without storing them, VerifyError
+// with the first mismatching chunk and the offset in the input is returned when they differ.
+// Chunks of splits changing records, e.g. by filters or conversions, don't match the input.
+func (s Splitter) Verify(inputFilePath string, chunks []string) error {
+	file, err := s.fileOp.Open(inputFilePath)
+	if err != nil {
+		return &InputError{Op: "open", Path: inputFilePath, Err: err}
+	}
+	defer file.Close()
+	v := &verifier{
+		source: bufio.NewReaderSize(file, s.bufferSize),
+		buf:    make([]byte, s.bufferSize),
+		path:   inputFilePath,
+	}
+	var header []byte
+	for i, path := range chunks {
+		chunk, err := s.fileOp.Open(path)
+		if err != nil {
+			return &InputError{Op: "open", Path: path, Err: err}
+		}
+		v.chunk = path
+		header, err = s.joinChunk(chunk, v, header, i == 0)
+		chunk.Close()
+		var verifyErr *VerifyError
+		var inputErr *InputError
+		switch {
+		case errors.As(err, &verifyErr):
+			return verifyErr
+		case errors.Is(err, ErrHeadersMismatch):
+			// the header copy isn't compared with the source, so the chunk differs where its data starts
+			return &VerifyError{Chunk: path, Offset: v.offset}
+		case errors.As(err, &inputErr) && inputErr.Path == inputFilePath:
+			return inputErr
+		case err != nil:
+			return fmt.Errorf("Couldn't read chunk %s : %w", path, err)
+		}
+	}
+	if _, err := v.source.ReadByte(); err != io.EOF {
+		if err != nil {
+			return &InputError{Op: "read", Path: inputFilePath, Offset: v.offset, Err: err}
+		}
+		return &VerifyError{Offset: v.offset}
+	}
+
+	return nil
+}
+
+// verifier compares data written to it with the source
+type verifier struct {
+	source *bufio.Reader
+	buf    []byte
+	path   string // path of the source
+	chunk  string // path of the chunk which is being compared
+	offset int64  // position in the source
+}
+
+func (v *verifier) Write(data []byte) (int, error) {
+	written := 0
+	for written < len(data) {
+		expected := v.buf[:min(len(data)-written, len(v.buf))]
+		n, err := io.ReadFull(v.source, expected)
+		for i := 0; i < n; i++ {
+			if data[written+i] != expected[i] {
+				return written + i, &VerifyError{Chunk: v.chunk, Offset: v.offset + int64(i)}
+			}
+		}
+		written += n
+		v.offset += int64(n)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			// the chunk contains more data than the source
+			return written, &VerifyError{Chunk: v.chunk, Offset: v.offset}
+		}
+		if err != nil {
+			return written, &InputError{Op: "read", Path: v.path, Offset: v.offset, Err: err}
+		}
+	}
+
+	return written, nil
+}
@@ -0,0 +1,107 @@
+package split_csv
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Verify(t *testing.T) {
+	split := func(t *testing.T, s Splitter) []string {
+		chunks, err := s.Split("testdata/test.csv", t.TempDir())
+		require.NoError(t, err)
+		require.Greater(t, len(chunks), 2)
+		return chunks
+	}
+	t.Run("It verifies chunks of the source", func(t *testing.T) {
+		for _, withHeader := range []bool{true, false} {
+			s := newTestSplitter(";", 400)
+			s.WithHeader = withHeader
+			chunks := split(t, s)
+
+			assert.NoError(t, s.Verify("testdata/test.csv", chunks))
+		}
+	})
+	t.Run("It verifies chunks with trailers", func(t *testing.T) {
+		// the trailer is written on a new line, so the source should end with it
+		source, err := os.ReadFile("testdata/test.csv")
+		require.NoError(t, err)
+		path := filepath.Join(t.TempDir(), "test.csv")
+		require.NoError(t, os.WriteFile(path, append(source, '\n'), 0o644))
+		s := newTestSplitter(";", 400)
+		s.ChunkTrailer = "TOTAL;%d"
+		chunks, err := s.Split(path, t.TempDir())
+		require.NoError(t, err)
+
+		assert.NoError(t, s.Verify(path, chunks))
+		assert.Error(t, s.Verify("testdata/test.csv", chunks))
+	})
+	t.Run("It reports the first mismatching chunk", func(t *testing.T) {
+		s := newTestSplitter(";", 400)
+		chunks := split(t, s)
+		data, err := os.ReadFile(chunks[1])
+		require.NoError(t, err)
+		first, err := os.ReadFile(chunks[0])
+		require.NoError(t, err)
+		data[bytes.IndexByte(data, '\n')+4] = 'X'
+		require.NoError(t, os.WriteFile(chunks[1], data, 0o644))
+
+		err = s.Verify("testdata/test.csv", chunks)
+
+		var verifyErr *VerifyError
+		require.True(t, errors.As(err, &verifyErr))
+		assert.Equal(t, chunks[1], verifyErr.Chunk)
+		assert.Equal(t, int64(len(first)+3), verifyErr.Offset)
+	})
+	t.Run("It reports the chunk with a different header", func(t *testing.T) {
+		s := newTestSplitter(";", 400)
+		chunks := split(t, s)
+		data, err := os.ReadFile(chunks[1])
+		require.NoError(t, err)
+		first, err := os.ReadFile(chunks[0])
+		require.NoError(t, err)
+		data[0] = 'X'
+		require.NoError(t, os.WriteFile(chunks[1], data, 0o644))
+
+		err = s.Verify("testdata/test.csv", chunks)
+
+		var verifyErr *VerifyError
+		require.True(t, errors.As(err, &verifyErr))
+		assert.Equal(t, chunks[1], verifyErr.Chunk)
+		assert.Equal(t, int64(len(first)), verifyErr.Offset)
+	})
+	t.Run("It reports missing and extra data", func(t *testing.T) {
+		s := newTestSplitter(";", 400)
+		chunks := split(t, s)
+		source, err := os.ReadFile("testdata/test.csv")
+		require.NoError(t, err)
+		last, err := os.ReadFile(chunks[len(chunks)-1])
+		require.NoError(t, err)
+
+		err = s.Verify("testdata/test.csv", chunks[:len(chunks)-1])
+		var verifyErr *VerifyError
+		require.True(t, errors.As(err, &verifyErr))
+		assert.Empty(t, verifyErr.Chunk)
+		headerSize := bytes.IndexByte(last, '\n') + 1
+		assert.Equal(t, int64(len(source)-len(last)+headerSize), verifyErr.Offset)
+
+		extra := filepath.Join(t.TempDir(), "extra.csv")
+		require.NoError(t, os.WriteFile(extra, append(last, "\n42"...), 0o644))
+		err = s.Verify("testdata/test.csv", append(chunks[:len(chunks)-1], extra))
+		require.True(t, errors.As(err, &verifyErr))
+		assert.Equal(t, extra, verifyErr.Chunk)
+		assert.Equal(t, int64(len(source)), verifyErr.Offset)
+	})
+	t.Run("It returns errors of chunks", func(t *testing.T) {
+		err := newTestSplitter(";", 400).Verify("testdata/test.csv", []string{"testdata/missing.csv"})
+
+		var inputErr *InputError
+		require.True(t, errors.As(err, &inputErr))
+		assert.Equal(t, "testdata/missing.csv", inputErr.Path)
+	})
+}
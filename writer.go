@@ -0,0 +1,106 @@
+package split_csv
+
+import (
+	"errors"
+)
+
+var ErrWriterClosed = errors.New("split writer is closed")
+
+// SplitWriter splits data written to it in chunks like SplitReader does with data of a reader.
+// The last chunk is completed on Close.
+type SplitWriter struct {
+	s      Splitter
+	st     *state
+	buf    []byte // data which isn't split yet
+	result *Result
+	err    error
+	closed bool
+}
+
+// NewSplitWriter initializes the writer which splits data written to it in chunks in the output directory
+func (s Splitter) NewSplitWriter(outputDirPath string, outputFilePrefix string) (*SplitWriter, error) {
+	if s.Parts > 0 {
+		return nil, ErrPartsNotSupported
+	}
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	s = s.withDryRun()
+	st, err := s.start(outputDirPath, outputFilePrefix, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SplitWriter{
+		s:   s,
+		st:  st,
+		buf: make([]byte, 0, s.bufferSize),
+	}, nil
+}
+
+// Write splits the data saving completed chunks, data is split by bulks of the splitter buffer size
+func (w *SplitWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+	w.buf = append(w.buf, p...)
+	consumed := 0
+	for len(w.buf)-consumed >= w.s.bufferSize {
+		bulk := w.buf[consumed : consumed+w.s.bufferSize]
+		w.st.offset += int64(len(bulk))
+		if err := w.s.consume(w.st, bulk); err != nil {
+			w.fail(err)
+			return 0, err
+		}
+		consumed += len(bulk)
+	}
+	w.buf = append(w.buf[:0], w.buf[consumed:]...)
+
+	return len(p), nil
+}
+
+// Close splits the rest of the data and completes the last chunk
+func (w *SplitWriter) Close() error {
+	if w.err != nil || w.closed {
+		return w.err
+	}
+	w.closed = true
+	defer w.st.closeDedup()
+	w.st.offset += int64(len(w.buf))
+	result, err := w.s.finish(w.st, w.buf)
+	if err != nil {
+		w.fail(err)
+		return err
+	}
+	w.result = result
+	w.buf = nil
+
+	return nil
+}
+
+// Chunks returns paths of the created chunk files, it's nil until the writer is closed
+func (w *SplitWriter) Chunks() []string {
+	if w.result == nil {
+		return nil
+	}
+
+	return w.result.Chunks
+}
+
+// Result returns details of the split, it's nil until the writer is closed
+func (w *SplitWriter) Result() *Result {
+	return w.result
+}
+
+// fail stops splitting with the error
+func (w *SplitWriter) fail(err error) {
+	w.err = err
+	w.st.closeDedup()
+	w.st.closeRejects()
+	if w.st.chunkFile != nil {
+		w.st.chunkFile.Close()
+	}
+}
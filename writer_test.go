@@ -0,0 +1,71 @@
+package split_csv
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SplitWriter_integration(t *testing.T) {
+	input, err := os.ReadFile("testdata/test.csv")
+	require.NoError(t, err)
+	readAll := func(t *testing.T, chunks []string) []string {
+		result := make([]string, len(chunks))
+		for i, chunk := range chunks {
+			content, err := os.ReadFile(chunk)
+			require.NoError(t, err)
+			result[i] = string(content)
+		}
+		return result
+	}
+
+	t.Run("It splits written data like SplitReader", func(t *testing.T) {
+		s := newTestSplitter(";", 800)
+		s.bufferSize = 100
+		expected, err := s.SplitReader(bytes.NewReader(input), t.TempDir(), "test")
+		require.NoError(t, err)
+
+		dir := t.TempDir()
+		w, err := s.NewSplitWriter(dir, "test")
+		require.NoError(t, err)
+		for data := input; len(data) > 0; {
+			n := min(7, len(data))
+			written, err := w.Write(data[:n])
+			require.NoError(t, err)
+			assert.Equal(t, n, written)
+			data = data[n:]
+		}
+		assert.Nil(t, w.Chunks())
+		require.NoError(t, w.Close())
+
+		require.Len(t, w.Chunks(), len(expected))
+		assert.Equal(t, filepath.Join(dir, "test_1.csv"), w.Chunks()[0])
+		assert.Equal(t, readAll(t, expected), readAll(t, w.Chunks()))
+		assert.Equal(t, w.Chunks(), w.Result().Chunks)
+	})
+	t.Run("It doesn't accept data after Close", func(t *testing.T) {
+		s := newTestSplitter(";", 800)
+		s.bufferSize = 100
+		w, err := s.NewSplitWriter(t.TempDir(), "test")
+		require.NoError(t, err)
+		_, err = w.Write(input)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		_, err = w.Write(input)
+		assert.ErrorIs(t, err, ErrWriterClosed)
+		assert.NoError(t, w.Close())
+	})
+	t.Run("It fails on wrong options", func(t *testing.T) {
+		s := newTestSplitter(";", 800)
+		s.bufferSize = 100
+		s.Parts = 2
+		_, err := s.NewSplitWriter(t.TempDir(), "test")
+
+		assert.ErrorIs(t, err, ErrPartsNotSupported)
+	})
+}